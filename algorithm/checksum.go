@@ -0,0 +1,154 @@
+package algorithm
+
+import (
+	"hash/adler32"
+)
+
+// The Append functions append the checksum to data, multi-byte checksums in
+// little endian like AppendCrc16, and the Verify functions check data which
+// ends with such a checksum.
+
+// Sum8 returns the sum of all bytes modulo 256.
+func Sum8(data []byte) byte {
+	var sum byte
+	for _, b := range data {
+		sum += b
+	}
+	return sum
+}
+
+// Sum8Complement returns the two's complement of Sum8, so the sum of data
+// and the checksum is 0.
+func Sum8Complement(data []byte) byte {
+	return -Sum8(data)
+}
+
+// Xor8 returns the XOR of all bytes, also known as BCC.
+func Xor8(data []byte) byte {
+	var x byte
+	for _, b := range data {
+		x ^= b
+	}
+	return x
+}
+
+// LRC returns the longitudinal redundancy check of Modbus ASCII, computed
+// over the binary bytes of the message (before hex encoding). It is the same
+// as Sum8Complement.
+func LRC(data []byte) byte {
+	return Sum8Complement(data)
+}
+
+// Fletcher16 returns the Fletcher-16 checksum, the second sum in the high
+// byte.
+func Fletcher16(data []byte) uint16 {
+	var s1, s2 uint32
+	for len(data) > 0 {
+		// 5802 bytes can be summed before s2 overflows uint32
+		n := len(data)
+		if n > 5802 {
+			n = 5802
+		}
+		for _, b := range data[:n] {
+			s1 += uint32(b)
+			s2 += s1
+		}
+		s1 %= 255
+		s2 %= 255
+		data = data[n:]
+	}
+	return uint16(s2<<8 | s1)
+}
+
+// Fletcher32 returns the Fletcher-32 checksum over little endian 16-bit
+// words, an odd length is padded with a zero byte.
+func Fletcher32(data []byte) uint32 {
+	var s1, s2 uint64
+	for i := 0; i < len(data); i += 2 {
+		w := uint64(data[i])
+		if i+1 < len(data) {
+			w |= uint64(data[i+1]) << 8
+		}
+		s1 += w
+		s2 += s1
+		if i&0xFFFF == 0xFFFE {
+			s1 %= 65535
+			s2 %= 65535
+		}
+	}
+	s1 %= 65535
+	s2 %= 65535
+	return uint32(s2<<16 | s1)
+}
+
+func Adler32(data []byte) uint32 {
+	return adler32.Checksum(data)
+}
+
+func AppendSum8(data []byte) []byte {
+	return append(data, Sum8(data))
+}
+
+func VerifySum8(data []byte) bool {
+	return len(data) >= 1 && Sum8(data[:len(data)-1]) == data[len(data)-1]
+}
+
+func AppendSum8Complement(data []byte) []byte {
+	return append(data, Sum8Complement(data))
+}
+
+func VerifySum8Complement(data []byte) bool {
+	return len(data) >= 1 && Sum8(data) == 0
+}
+
+func AppendXor8(data []byte) []byte {
+	return append(data, Xor8(data))
+}
+
+func VerifyXor8(data []byte) bool {
+	return len(data) >= 1 && Xor8(data) == 0
+}
+
+func AppendLRC(data []byte) []byte {
+	return append(data, LRC(data))
+}
+
+func VerifyLRC(data []byte) bool {
+	return VerifySum8Complement(data)
+}
+
+func AppendFletcher16(data []byte) []byte {
+	sum := Fletcher16(data)
+	return append(data, byte(sum), byte(sum>>8))
+}
+
+func VerifyFletcher16(data []byte) bool {
+	n := len(data) - 2
+	return n >= 0 && Fletcher16(data[:n]) == uint16(data[n])|uint16(data[n+1])<<8
+}
+
+func AppendFletcher32(data []byte) []byte {
+	return appendUint32(data, Fletcher32(data))
+}
+
+func VerifyFletcher32(data []byte) bool {
+	n := len(data) - 4
+	return n >= 0 && Fletcher32(data[:n]) == getUint32(data[n:])
+}
+
+func AppendAdler32(data []byte) []byte {
+	return appendUint32(data, Adler32(data))
+}
+
+func VerifyAdler32(data []byte) bool {
+	n := len(data) - 4
+	return n >= 0 && Adler32(data[:n]) == getUint32(data[n:])
+}
+
+func appendUint32(data []byte, v uint32) []byte {
+	return append(data, byte(v), byte(v>>8), byte(v>>16), byte(v>>24))
+}
+
+func getUint32(b []byte) uint32 {
+	return uint32(b[0]) | uint32(b[1])<<8 | uint32(b[2])<<16 | uint32(b[3])<<24
+}
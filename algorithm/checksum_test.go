@@ -0,0 +1,96 @@
+package algorithm
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSum8(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal(Sum8(nil), byte(0))
+	assert.Equal(Sum8([]byte{0x01, 0x02, 0xFF}), byte(0x02))
+	assert.Equal(Sum8(testData), byte(0x80))
+	assert.Equal(Sum8Complement([]byte{0x01, 0x02, 0xFF}), byte(0xFE))
+	assert.Equal(Sum8Complement(nil), byte(0))
+}
+
+func TestXor8(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal(Xor8(nil), byte(0))
+	assert.Equal(Xor8([]byte{0x01, 0x02, 0x04, 0x01}), byte(0x06))
+	assert.Equal(Xor8(testData), byte(0))
+}
+
+func TestLRC(t *testing.T) {
+	assert := assert.New(t)
+	// Modbus ASCII ":010300000001FB"
+	assert.Equal(LRC([]byte{0x01, 0x03, 0x00, 0x00, 0x00, 0x01}), byte(0xFB))
+	// ":F7031389000A60"
+	assert.Equal(LRC([]byte{0xF7, 0x03, 0x13, 0x89, 0x00, 0x0A}), byte(0x60))
+}
+
+func TestFletcher(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal(Fletcher16([]byte("abcde")), uint16(0xC8F0))
+	assert.Equal(Fletcher16([]byte("abcdef")), uint16(0x2057))
+	assert.Equal(Fletcher16([]byte("abcdefgh")), uint16(0x0627))
+	assert.Equal(Fletcher16(nil), uint16(0))
+	assert.Equal(Fletcher32([]byte("abcde")), uint32(0xF04FC729))
+	assert.Equal(Fletcher32([]byte("abcdef")), uint32(0x56502D2A))
+	assert.Equal(Fletcher32([]byte("abcdefgh")), uint32(0xEBE19591))
+	assert.Equal(Fletcher32(nil), uint32(0))
+
+	// long input against the naive definition
+	data := make([]byte, 300000)
+	for i := range data {
+		data[i] = byte(0xFF - i%7)
+	}
+	var a, b uint32
+	for _, c := range data {
+		a = (a + uint32(c)) % 255
+		b = (b + a) % 255
+	}
+	assert.Equal(Fletcher16(data), uint16(b<<8|a))
+	a, b = 0, 0
+	for i := 0; i < len(data); i += 2 {
+		a = (a + (uint32(data[i]) | uint32(data[i+1])<<8)) % 65535
+		b = (b + a) % 65535
+	}
+	assert.Equal(Fletcher32(data), b<<16|a)
+}
+
+func TestAdler32(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal(Adler32([]byte("Wikipedia")), uint32(0x11E60398))
+	assert.Equal(Adler32(nil), uint32(1))
+}
+
+func TestAppendVerifyChecksum(t *testing.T) {
+	assert := assert.New(t)
+	for _, c := range []struct {
+		append func([]byte) []byte
+		verify func([]byte) bool
+		size   int
+	}{
+		{AppendSum8, VerifySum8, 1},
+		{AppendSum8Complement, VerifySum8Complement, 1},
+		{AppendXor8, VerifyXor8, 1},
+		{AppendLRC, VerifyLRC, 1},
+		{AppendFletcher16, VerifyFletcher16, 2},
+		{AppendFletcher32, VerifyFletcher32, 4},
+		{AppendAdler32, VerifyAdler32, 4},
+	} {
+		data := c.append(append([]byte(nil), testData[:100]...))
+		assert.Len(data, 100+c.size)
+		assert.True(c.verify(data))
+		data[50]++
+		assert.False(c.verify(data))
+		assert.False(c.verify(nil))
+	}
+	data := AppendFletcher16([]byte("abcde"))
+	assert.Equal(data[5:], []byte{0xF0, 0xC8})
+	data = AppendAdler32([]byte("Wikipedia"))
+	assert.Equal(data[9:], []byte{0x98, 0x03, 0xE6, 0x11})
+	assert.Equal(AppendSum8Complement([]byte{0x01, 0x02, 0xFF}),
+		[]byte{0x01, 0x02, 0xFF, 0xFE})
+}
@@ -0,0 +1,195 @@
+package core
+
+import (
+	"errors"
+	"math"
+)
+
+var (
+	ErrDivisionByZero = errors.New("division by zero")
+	ErrOverflow       = errors.New("overflow")
+	ErrNaN            = errors.New("result is NaN")
+	ErrInvalidRange   = errors.New("invalid range")
+)
+
+// MathError is returned by the strict Arith operations, Err is one of
+// ErrDivisionByZero, ErrOverflow, ErrNaN or ErrInvalidRange.
+type MathError struct {
+	Op  string
+	Err error
+}
+
+func (e *MathError) Error() string {
+	return "core.Number." + e.Op + ": " + e.Err.Error()
+}
+
+func (e *MathError) Unwrap() error {
+	return e.Err
+}
+
+// Arith performs arithmetic on Numbers. When Strict is false the results
+// follow IEEE 754 like plain float64 and the error is always nil. When
+// Strict is set an error is returned instead of a result which is NaN, an
+// infinity caused by overflow or a division by zero, so bad sensor data can
+// be detected instead of propagated.
+type Arith struct {
+	Strict bool
+}
+
+var (
+	DefaultArith = Arith{}
+	StrictArith  = Arith{Strict: true}
+)
+
+func (m Arith) check(op string, r Number, operands ...Number) (Number, error) {
+	if !m.Strict {
+		return r, nil
+	}
+	if math.IsNaN(float64(r)) {
+		return r, &MathError{op, ErrNaN}
+	}
+	if math.IsInf(float64(r), 0) {
+		for _, v := range operands {
+			if math.IsInf(float64(v), 0) {
+				return r, nil
+			}
+		}
+		return r, &MathError{op, ErrOverflow}
+	}
+	return r, nil
+}
+
+func (m Arith) Add(a, b Number) (Number, error) {
+	return m.check("Add", a+b, a, b)
+}
+
+func (m Arith) Sub(a, b Number) (Number, error) {
+	return m.check("Sub", a-b, a, b)
+}
+
+func (m Arith) Mul(a, b Number) (Number, error) {
+	return m.check("Mul", a*b, a, b)
+}
+
+func (m Arith) Div(a, b Number) (Number, error) {
+	if m.Strict && b == 0 {
+		return a / b, &MathError{"Div", ErrDivisionByZero}
+	}
+	return m.check("Div", a/b, a, b)
+}
+
+func (m Arith) Pow(a, b Number) (Number, error) {
+	r := Number(math.Pow(float64(a), float64(b)))
+	if m.Strict && a == 0 && b < 0 {
+		return r, &MathError{"Pow", ErrDivisionByZero}
+	}
+	return m.check("Pow", r, a, b)
+}
+
+// Round rounds v to places decimal places using mode, see Number.Round.
+func (m Arith) Round(v Number, places int, mode RoundingMode) (Number, error) {
+	return m.check("Round", roundDecimal(v, places, mode), v)
+}
+
+// RoundSignificant rounds v to digits significant digits using mode, see
+// Number.RoundSignificant.
+func (m Arith) RoundSignificant(v Number, digits int,
+	mode RoundingMode) (Number, error) {
+	if m.Strict && digits < 1 {
+		return v, &MathError{"RoundSignificant", ErrInvalidRange}
+	}
+	return m.check("RoundSignificant", v.RoundSignificant(digits, mode), v)
+}
+
+// Floor rounds v down to a multiple of step. A zero step returns v, or an
+// error in strict mode.
+func (m Arith) Floor(v, step Number) (Number, error) {
+	if step == 0 {
+		if m.Strict {
+			return v, &MathError{"Floor", ErrDivisionByZero}
+		}
+		return v, nil
+	}
+	return m.check("Floor",
+		Number(math.Floor(float64(v/step)))*step, v, step)
+}
+
+// Ceil rounds v up to a multiple of step. A zero step returns v, or an error
+// in strict mode.
+func (m Arith) Ceil(v, step Number) (Number, error) {
+	if step == 0 {
+		if m.Strict {
+			return v, &MathError{"Ceil", ErrDivisionByZero}
+		}
+		return v, nil
+	}
+	return m.check("Ceil",
+		Number(math.Ceil(float64(v/step)))*step, v, step)
+}
+
+// Clamp limits v to [min, max]. In strict mode min > max is an error.
+func (m Arith) Clamp(v, min, max Number) (Number, error) {
+	if m.Strict && min > max {
+		return v, &MathError{"Clamp", ErrInvalidRange}
+	}
+	r := v
+	if r > max {
+		r = max
+	}
+	if r < min {
+		r = min
+	}
+	return m.check("Clamp", r, v, min, max)
+}
+
+func (v Number) IsNaN() bool {
+	return math.IsNaN(float64(v))
+}
+
+// IsInf reports whether v is an infinity, according to sign as math.IsInf.
+func (v Number) IsInf(sign int) bool {
+	return math.IsInf(float64(v), sign)
+}
+
+// The following methods use DefaultArith, use StrictArith to get errors
+// instead of NaN or Inf.
+
+func (v Number) Add(o Number) Number {
+	r, _ := DefaultArith.Add(v, o)
+	return r
+}
+
+func (v Number) Sub(o Number) Number {
+	r, _ := DefaultArith.Sub(v, o)
+	return r
+}
+
+func (v Number) Mul(o Number) Number {
+	r, _ := DefaultArith.Mul(v, o)
+	return r
+}
+
+func (v Number) Div(o Number) Number {
+	r, _ := DefaultArith.Div(v, o)
+	return r
+}
+
+func (v Number) Pow(o Number) Number {
+	r, _ := DefaultArith.Pow(v, o)
+	return r
+}
+
+func (v Number) FloorStep(step Number) Number {
+	r, _ := DefaultArith.Floor(v, step)
+	return r
+}
+
+func (v Number) CeilStep(step Number) Number {
+	r, _ := DefaultArith.Ceil(v, step)
+	return r
+}
+
+func (v Number) Clamp(min, max Number) Number {
+	r, _ := DefaultArith.Clamp(v, min, max)
+	return r
+}
@@ -0,0 +1,110 @@
+package core
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+func TestNumberArith(t *testing.T) {
+	assert := assert.New(t)
+	assert.EqualValues(Number(1.5).Add(2), 3.5)
+	assert.EqualValues(Number(1.5).Sub(2), -0.5)
+	assert.EqualValues(Number(1.5).Mul(2), 3)
+	assert.EqualValues(Number(3).Div(2), 1.5)
+	assert.EqualValues(Number(2).Pow(10), 1024)
+	assert.True(Number(1).Div(0).IsInf(1))
+	assert.True(Number(-1).Div(0).IsInf(-1))
+	assert.True(Number(0).Div(0).IsNaN())
+	assert.False(Number(1).IsNaN())
+	assert.False(Number(1).IsInf(0))
+}
+
+func TestNumberStep(t *testing.T) {
+	assert := assert.New(t)
+	assert.EqualValues(Number(7.3).FloorStep(0.5), 7)
+	assert.EqualValues(Number(7.3).CeilStep(0.5), 7.5)
+	assert.EqualValues(Number(-7.3).FloorStep(0.5), -7.5)
+	assert.EqualValues(Number(-7.3).CeilStep(0.5), -7)
+	assert.EqualValues(Number(17).FloorStep(5), 15)
+	assert.EqualValues(Number(17).CeilStep(5), 20)
+	assert.EqualValues(Number(17).FloorStep(0), 17)
+	assert.EqualValues(Number(17).CeilStep(0), 17)
+}
+
+func TestNumberClamp(t *testing.T) {
+	assert := assert.New(t)
+	assert.EqualValues(Number(5).Clamp(0, 10), 5)
+	assert.EqualValues(Number(-5).Clamp(0, 10), 0)
+	assert.EqualValues(Number(15).Clamp(0, 10), 10)
+	assert.EqualValues(Number(math.Inf(1)).Clamp(0, 10), 10)
+}
+
+func TestStrictArith(t *testing.T) {
+	assert := assert.New(t)
+	m := StrictArith
+	v, err := m.Add(1, 2)
+	assert.NoError(err)
+	assert.EqualValues(v, 3)
+	_, err = m.Div(1, 0)
+	assert.EqualError(err, "core.Number.Div: division by zero")
+	assert.True(errors.Is(err, ErrDivisionByZero))
+	_, err = m.Div(0, 0)
+	assert.True(errors.Is(err, ErrDivisionByZero))
+	_, err = m.Mul(1e200, 1e200)
+	assert.EqualError(err, "core.Number.Mul: overflow")
+	assert.True(errors.Is(err, ErrOverflow))
+	_, err = m.Add(math.MaxFloat64, math.MaxFloat64)
+	assert.True(errors.Is(err, ErrOverflow))
+	_, err = m.Sub(Number(math.NaN()), 1)
+	assert.EqualError(err, "core.Number.Sub: result is NaN")
+	var me *MathError
+	assert.True(errors.As(err, &me))
+	assert.Equal(me.Op, "Sub")
+	// an infinite operand is not an overflow
+	v, err = m.Add(Number(math.Inf(1)), 1)
+	assert.NoError(err)
+	assert.True(v.IsInf(1))
+	_, err = m.Add(Number(math.Inf(1)), Number(math.Inf(-1)))
+	assert.True(errors.Is(err, ErrNaN))
+	_, err = m.Pow(10, 400)
+	assert.True(errors.Is(err, ErrOverflow))
+	_, err = m.Pow(-8, 1.0/3)
+	assert.True(errors.Is(err, ErrNaN))
+	_, err = m.Pow(0, -1)
+	assert.True(errors.Is(err, ErrDivisionByZero))
+	v, err = m.Round(1.25, 1, RoundHalfEven)
+	assert.NoError(err)
+	assert.EqualValues(v, 1.2)
+	_, err = m.Round(Number(math.NaN()), 1, RoundHalfUp)
+	assert.EqualError(err, "core.Number.Round: result is NaN")
+	_, err = m.Round(math.MaxFloat64, -308, RoundCeiling)
+	assert.True(errors.Is(err, ErrOverflow))
+	v, err = m.RoundSignificant(1234, 2, RoundFloor)
+	assert.NoError(err)
+	assert.EqualValues(v, 1200)
+	_, err = m.RoundSignificant(1234, 0, RoundFloor)
+	assert.EqualError(err, "core.Number.RoundSignificant: invalid range")
+	_, err = m.Floor(1, 0)
+	assert.EqualError(err, "core.Number.Floor: division by zero")
+	_, err = m.Ceil(1, 0)
+	assert.EqualError(err, "core.Number.Ceil: division by zero")
+	v, err = m.Ceil(1.1, 0.5)
+	assert.NoError(err)
+	assert.EqualValues(v, 1.5)
+	_, err = m.Clamp(1, 10, 0)
+	assert.EqualError(err, "core.Number.Clamp: invalid range")
+	_, err = m.Clamp(Number(math.NaN()), 0, 10)
+	assert.True(errors.Is(err, ErrNaN))
+	v, err = m.Clamp(11, 0, 10)
+	assert.NoError(err)
+	assert.EqualValues(v, 10)
+
+	v, err = DefaultArith.Div(1, 0)
+	assert.NoError(err)
+	assert.True(v.IsInf(1))
+	v, err = DefaultArith.Clamp(5, 10, 0)
+	assert.NoError(err)
+	assert.EqualValues(v, 10)
+}
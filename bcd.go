@@ -0,0 +1,228 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// BCDSign selects where the sign nibble of a BCD value is stored.
+type BCDSign int
+
+const (
+	// BCDUnsigned has no sign nibble, negative values can not be encoded.
+	BCDUnsigned BCDSign = iota
+	// BCDSignLeading stores the sign in the first nibble.
+	BCDSignLeading
+	// BCDSignTrailing stores the sign in the last nibble, like COBOL COMP-3.
+	BCDSignTrailing
+)
+
+// Sign nibbles written by the encoders. When decoding, 0xA, 0xC, 0xE and 0xF
+// are accepted as positive and 0xB and 0xD as negative.
+const (
+	BCDPositive byte = 0xC
+	BCDNegative byte = 0xD
+)
+
+// BCDNibbleError reports a nibble which is neither a decimal digit nor a
+// valid sign. Index counts nibbles from the start of the data, the high
+// nibble of each byte first.
+type BCDNibbleError struct {
+	Index  int
+	Nibble byte
+}
+
+func (e BCDNibbleError) Error() string {
+	return fmt.Sprintf("core.BCD: invalid nibble 0x%X at byte %d %s",
+		e.Nibble, e.Index/2, []string{"high", "low"}[e.Index%2])
+}
+
+// bcdDigits returns the decimal digits of |v| * 10^decimals, rounded and
+// left padded with zeros to exactly digits digits.
+func bcdDigits(v Number, digits, decimals int, sign BCDSign) (
+	bool, []byte, error) {
+	if digits < 1 || decimals < 0 {
+		return false, nil, errors.New("core.BCD: invalid digit count")
+	}
+	f := float64(v)
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return false, nil, errors.New("core.BCD: can not encode NaN or Inf")
+	}
+	s := strings.Replace(
+		strconv.FormatFloat(math.Abs(f), 'f', decimals, 64), ".", "", 1)
+	s = strings.TrimLeft(s, "0")
+	if len(s) > digits {
+		return false, nil, fmt.Errorf(
+			"core.BCD: %v does not fit in %d digits", f, digits)
+	}
+	negative := f < 0 && len(s) > 0
+	if negative && sign == BCDUnsigned {
+		return false, nil, fmt.Errorf(
+			"core.BCD: can not encode negative value %v as unsigned", f)
+	}
+	ret := make([]byte, digits)
+	pad := digits - len(s)
+	for i := range ret {
+		if i >= pad {
+			ret[i] = s[i-pad] - '0'
+		}
+	}
+	return negative, ret, nil
+}
+
+func bcdSignNibble(negative bool) byte {
+	if negative {
+		return BCDNegative
+	}
+	return BCDPositive
+}
+
+func bcdDecodeSign(nibble byte, index int) (bool, error) {
+	switch nibble {
+	case 0xA, 0xC, 0xE, 0xF:
+		return false, nil
+	case 0xB, 0xD:
+		return true, nil
+	}
+	return false, BCDNibbleError{index, nibble}
+}
+
+func bcdNumber(negative bool, digits []byte, decimals int) Number {
+	s := make([]byte, 0, len(digits)+8)
+	if negative {
+		s = append(s, '-')
+	}
+	s = append(s, '0')
+	for _, d := range digits {
+		s = append(s, '0'+d)
+	}
+	s = append(s, 'e', '-')
+	s = strconv.AppendInt(s, int64(decimals), 10)
+	f, _ := strconv.ParseFloat(string(s), 64)
+	return Number(f)
+}
+
+// EncodePackedBCD encodes v as packed BCD with two digits per byte. digits is
+// the total digit count and the last decimals digits are behind the implied
+// decimal point, so 12.34 with 5 digits and 2 decimals is 0x01 0x23 0x4C
+// when a trailing sign is used. A zero nibble is prepended when the digits
+// and the sign do not fill a whole number of bytes.
+func EncodePackedBCD(v Number, digits, decimals int, sign BCDSign) (
+	[]byte, error) {
+	negative, ds, err := bcdDigits(v, digits, decimals, sign)
+	if err != nil {
+		return nil, err
+	}
+	count := len(ds)
+	if sign != BCDUnsigned {
+		count++
+	}
+	if count%2 == 1 {
+		ds = append([]byte{0}, ds...)
+	}
+	switch sign {
+	case BCDSignLeading:
+		ds = append([]byte{bcdSignNibble(negative)}, ds...)
+	case BCDSignTrailing:
+		ds = append(ds, bcdSignNibble(negative))
+	}
+	ret := make([]byte, len(ds)/2)
+	for i := range ret {
+		ret[i] = ds[2*i]<<4 | ds[2*i+1]
+	}
+	return ret, nil
+}
+
+// DecodePackedBCD is the reverse of EncodePackedBCD, all nibbles except the
+// sign nibble must be decimal digits.
+func DecodePackedBCD(data []byte, decimals int, sign BCDSign) (Number, error) {
+	if len(data) == 0 {
+		return 0, errors.New("core.BCD: no data")
+	}
+	nibbles := make([]byte, 0, len(data)*2)
+	for _, b := range data {
+		nibbles = append(nibbles, b>>4, b&0x0F)
+	}
+	negative := false
+	first := 0
+	var err error
+	switch sign {
+	case BCDSignLeading:
+		if negative, err = bcdDecodeSign(nibbles[0], 0); err != nil {
+			return 0, err
+		}
+		first = 1
+	case BCDSignTrailing:
+		last := len(nibbles) - 1
+		if negative, err = bcdDecodeSign(nibbles[last], last); err != nil {
+			return 0, err
+		}
+		nibbles = nibbles[:last]
+	}
+	for i := first; i < len(nibbles); i++ {
+		if nibbles[i] > 9 {
+			return 0, BCDNibbleError{i, nibbles[i]}
+		}
+	}
+	return bcdNumber(negative, nibbles[first:], decimals), nil
+}
+
+// EncodeUnpackedBCD encodes v as unpacked BCD with one digit per byte in the
+// low nibble. The sign, if any, takes an extra byte holding BCDPositive or
+// BCDNegative.
+func EncodeUnpackedBCD(v Number, digits, decimals int, sign BCDSign) (
+	[]byte, error) {
+	negative, ds, err := bcdDigits(v, digits, decimals, sign)
+	if err != nil {
+		return nil, err
+	}
+	switch sign {
+	case BCDSignLeading:
+		ds = append([]byte{bcdSignNibble(negative)}, ds...)
+	case BCDSignTrailing:
+		ds = append(ds, bcdSignNibble(negative))
+	}
+	return ds, nil
+}
+
+// DecodeUnpackedBCD is the reverse of EncodeUnpackedBCD, the high nibble of
+// every byte must be zero.
+func DecodeUnpackedBCD(data []byte, decimals int, sign BCDSign) (
+	Number, error) {
+	if len(data) == 0 {
+		return 0, errors.New("core.BCD: no data")
+	}
+	for i, b := range data {
+		if b>>4 != 0 {
+			return 0, BCDNibbleError{2 * i, b >> 4}
+		}
+	}
+	negative := false
+	var err error
+	switch sign {
+	case BCDSignLeading:
+		if negative, err = bcdDecodeSign(data[0], 1); err != nil {
+			return 0, err
+		}
+		data = data[1:]
+	case BCDSignTrailing:
+		last := len(data) - 1
+		if negative, err = bcdDecodeSign(data[last], 2*last+1); err != nil {
+			return 0, err
+		}
+		data = data[:last]
+	}
+	offset := 0
+	if sign == BCDSignLeading {
+		offset = 1
+	}
+	for i, b := range data {
+		if b > 9 {
+			return 0, BCDNibbleError{2*(i+offset) + 1, b}
+		}
+	}
+	return bcdNumber(negative, data, decimals), nil
+}
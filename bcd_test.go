@@ -0,0 +1,140 @@
+package core
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+func TestEncodePackedBCD(t *testing.T) {
+	assert := assert.New(t)
+	check := func(v Number, digits, decimals int, sign BCDSign,
+		expect []byte) {
+		data, err := EncodePackedBCD(v, digits, decimals, sign)
+		assert.NoError(err)
+		assert.Equal(expect, data, "%v", v)
+	}
+	check(1234, 4, 0, BCDUnsigned, []byte{0x12, 0x34})
+	check(123, 3, 0, BCDUnsigned, []byte{0x01, 0x23})
+	check(0, 2, 0, BCDUnsigned, []byte{0x00})
+	check(12.34, 5, 2, BCDSignTrailing, []byte{0x01, 0x23, 0x4C})
+	check(-12.34, 5, 2, BCDSignTrailing, []byte{0x01, 0x23, 0x4D})
+	check(-12.34, 4, 2, BCDSignTrailing, []byte{0x01, 0x23, 0x4D})
+	check(-12.34, 5, 2, BCDSignLeading, []byte{0xD0, 0x12, 0x34})
+	check(12.34, 4, 2, BCDSignLeading, []byte{0xC0, 0x12, 0x34})
+	check(12.345, 6, 3, BCDSignLeading, []byte{0xC0, 0x01, 0x23, 0x45})
+	check(0.5, 2, 2, BCDUnsigned, []byte{0x50})
+	check(1.006, 3, 2, BCDUnsigned, []byte{0x01, 0x01})
+	check(-0.001, 3, 2, BCDSignTrailing, []byte{0x00, 0x0C})
+	check(99999999, 8, 0, BCDUnsigned, []byte{0x99, 0x99, 0x99, 0x99})
+}
+
+func TestEncodePackedBCDError(t *testing.T) {
+	assert := assert.New(t)
+	_, err := EncodePackedBCD(100, 2, 0, BCDUnsigned)
+	assert.EqualError(err, "core.BCD: 100 does not fit in 2 digits")
+	_, err = EncodePackedBCD(9.996, 3, 2, BCDUnsigned)
+	assert.Error(err)
+	_, err = EncodePackedBCD(-1, 2, 0, BCDUnsigned)
+	assert.EqualError(err,
+		"core.BCD: can not encode negative value -1 as unsigned")
+	_, err = EncodePackedBCD(Number(math.NaN()), 2, 0, BCDUnsigned)
+	assert.Error(err)
+	_, err = EncodePackedBCD(Number(math.Inf(1)), 2, 0, BCDUnsigned)
+	assert.Error(err)
+	_, err = EncodePackedBCD(1, 0, 0, BCDUnsigned)
+	assert.Error(err)
+	_, err = EncodePackedBCD(1, 2, -1, BCDUnsigned)
+	assert.Error(err)
+}
+
+func TestDecodePackedBCD(t *testing.T) {
+	assert := assert.New(t)
+	check := func(data []byte, decimals int, sign BCDSign, expect Number) {
+		v, err := DecodePackedBCD(data, decimals, sign)
+		assert.NoError(err)
+		assert.Equal(expect, v, "% X", data)
+	}
+	check([]byte{0x12, 0x34}, 0, BCDUnsigned, 1234)
+	check([]byte{0x12, 0x34}, 2, BCDUnsigned, 12.34)
+	check([]byte{0x12, 0x34}, 5, BCDUnsigned, 0.01234)
+	check([]byte{0x01, 0x23, 0x4C}, 2, BCDSignTrailing, 12.34)
+	check([]byte{0x01, 0x23, 0x4D}, 2, BCDSignTrailing, -12.34)
+	check([]byte{0x01, 0x23, 0x4F}, 2, BCDSignTrailing, 12.34)
+	check([]byte{0x01, 0x23, 0x4B}, 1, BCDSignTrailing, -123.4)
+	check([]byte{0xD0, 0x12, 0x34}, 2, BCDSignLeading, -12.34)
+	check([]byte{0xA1, 0x23}, 0, BCDSignLeading, 123)
+	check([]byte{0x0C}, 0, BCDSignTrailing, 0)
+	check([]byte{0x99, 0x99, 0x99, 0x99, 0x99, 0x99, 0x99, 0x99, 0x99, 0x99},
+		0, BCDUnsigned, 99999999999999999999)
+	for _, sign := range []BCDSign{BCDUnsigned, BCDSignLeading,
+		BCDSignTrailing} {
+		for _, v := range []Number{0, 1, 0.01, 12.5, 999.99, 123456.78} {
+			data, err := EncodePackedBCD(v, 8, 2, sign)
+			assert.NoError(err)
+			d, err := DecodePackedBCD(data, 2, sign)
+			assert.NoError(err)
+			assert.Equal(v, d)
+		}
+	}
+}
+
+func TestDecodePackedBCDError(t *testing.T) {
+	assert := assert.New(t)
+	_, err := DecodePackedBCD(nil, 0, BCDUnsigned)
+	assert.Error(err)
+	_, err = DecodePackedBCD([]byte{0x12, 0x3A}, 0, BCDUnsigned)
+	assert.Equal(err, BCDNibbleError{3, 0xA})
+	assert.EqualError(err, "core.BCD: invalid nibble 0xA at byte 1 low")
+	_, err = DecodePackedBCD([]byte{0x12, 0xF4}, 0, BCDUnsigned)
+	assert.EqualError(err, "core.BCD: invalid nibble 0xF at byte 1 high")
+	_, err = DecodePackedBCD([]byte{0x12, 0x34}, 0, BCDSignTrailing)
+	assert.Equal(err, BCDNibbleError{3, 0x4})
+	_, err = DecodePackedBCD([]byte{0x12, 0x3C}, 0, BCDSignLeading)
+	assert.Equal(err, BCDNibbleError{0, 0x1})
+	_, err = DecodePackedBCD([]byte{0xCA, 0x34}, 0, BCDSignLeading)
+	assert.Equal(err, BCDNibbleError{1, 0xA})
+}
+
+func TestEncodeUnpackedBCD(t *testing.T) {
+	assert := assert.New(t)
+	data, err := EncodeUnpackedBCD(123, 4, 0, BCDUnsigned)
+	assert.NoError(err)
+	assert.Equal(data, []byte{0x00, 0x01, 0x02, 0x03})
+	data, err = EncodeUnpackedBCD(-1.5, 3, 1, BCDSignLeading)
+	assert.NoError(err)
+	assert.Equal(data, []byte{0x0D, 0x00, 0x01, 0x05})
+	data, err = EncodeUnpackedBCD(1.5, 2, 1, BCDSignTrailing)
+	assert.NoError(err)
+	assert.Equal(data, []byte{0x01, 0x05, 0x0C})
+	_, err = EncodeUnpackedBCD(1000, 3, 0, BCDSignTrailing)
+	assert.Error(err)
+	_, err = EncodeUnpackedBCD(-1, 3, 0, BCDUnsigned)
+	assert.Error(err)
+}
+
+func TestDecodeUnpackedBCD(t *testing.T) {
+	assert := assert.New(t)
+	v, err := DecodeUnpackedBCD([]byte{0x00, 0x01, 0x02, 0x03}, 0, BCDUnsigned)
+	assert.NoError(err)
+	assert.EqualValues(v, 123)
+	v, err = DecodeUnpackedBCD([]byte{0x0D, 0x00, 0x01, 0x05}, 1,
+		BCDSignLeading)
+	assert.NoError(err)
+	assert.EqualValues(v, -1.5)
+	v, err = DecodeUnpackedBCD([]byte{0x01, 0x05, 0x0C}, 1, BCDSignTrailing)
+	assert.NoError(err)
+	assert.EqualValues(v, 1.5)
+	_, err = DecodeUnpackedBCD(nil, 0, BCDUnsigned)
+	assert.Error(err)
+	_, err = DecodeUnpackedBCD([]byte{0x01, 0x32}, 0, BCDUnsigned)
+	assert.Equal(err, BCDNibbleError{2, 0x3})
+	_, err = DecodeUnpackedBCD([]byte{0x01, 0x0A}, 0, BCDUnsigned)
+	assert.Equal(err, BCDNibbleError{3, 0xA})
+	_, err = DecodeUnpackedBCD([]byte{0x0C, 0x01, 0x0A}, 0, BCDSignLeading)
+	assert.Equal(err, BCDNibbleError{5, 0xA})
+	_, err = DecodeUnpackedBCD([]byte{0x01, 0x02}, 0, BCDSignLeading)
+	assert.Equal(err, BCDNibbleError{1, 0x1})
+	_, err = DecodeUnpackedBCD([]byte{0x01, 0x02}, 0, BCDSignTrailing)
+	assert.Equal(err, BCDNibbleError{3, 0x2})
+}
@@ -0,0 +1,223 @@
+package core
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// BitOrder selects how bit offsets in a ByteArray are numbered.
+type BitOrder int
+
+const (
+	// MSB0 numbers bits from the most significant bit of the first byte,
+	// fields are big-endian bit streams, as in most protocol diagrams.
+	MSB0 BitOrder = iota
+	// LSB0 numbers bits from the least significant bit of the first byte,
+	// fields are little-endian bit streams, as in Intel format CAN signals.
+	LSB0
+)
+
+func (o BitOrder) String() string {
+	switch o {
+	case MSB0:
+		return "MSB0"
+	case LSB0:
+		return "LSB0"
+	}
+	return fmt.Sprintf("BitOrder(%d)", int(o))
+}
+
+// bitPos returns the byte index and the mask of bit i.
+func (o BitOrder) bitPos(i int) (int, byte) {
+	if o == MSB0 {
+		return i / 8, 0x80 >> uint(i%8)
+	}
+	return i / 8, 1 << uint(i%8)
+}
+
+func (ba ByteArray) checkBits(name string, offset, n int) error {
+	if offset < 0 || n < 0 || offset+n > len(ba)*8 {
+		return fmt.Errorf("core.ByteArray.%s: bits [%d, %d) out of range, "+
+			"have %d bits", name, offset, offset+n, len(ba)*8)
+	}
+	return nil
+}
+
+func (ba ByteArray) Bit(i int, order BitOrder) (bool, error) {
+	if err := ba.checkBits("Bit", i, 1); err != nil {
+		return false, err
+	}
+	index, mask := order.bitPos(i)
+	return ba[index]&mask != 0, nil
+}
+
+func (ba ByteArray) SetBit(i int, order BitOrder) error {
+	if err := ba.checkBits("SetBit", i, 1); err != nil {
+		return err
+	}
+	index, mask := order.bitPos(i)
+	ba[index] |= mask
+	return nil
+}
+
+func (ba ByteArray) ClearBit(i int, order BitOrder) error {
+	if err := ba.checkBits("ClearBit", i, 1); err != nil {
+		return err
+	}
+	index, mask := order.bitPos(i)
+	ba[index] &^= mask
+	return nil
+}
+
+func (ba ByteArray) ToggleBit(i int, order BitOrder) error {
+	if err := ba.checkBits("ToggleBit", i, 1); err != nil {
+		return err
+	}
+	index, mask := order.bitPos(i)
+	ba[index] ^= mask
+	return nil
+}
+
+func checkFieldWidth(name string, n int) error {
+	if n < 1 || n > 64 {
+		return fmt.Errorf("core.ByteArray.%s: invalid width %d", name, n)
+	}
+	return nil
+}
+
+// Uint reads the n-bit (1 to 64) unsigned field starting at bit offset. With
+// MSB0 the first bit is the most significant bit of the value, with LSB0 it
+// is the least significant one.
+func (ba ByteArray) Uint(offset, n int, order BitOrder) (uint64, error) {
+	if err := checkFieldWidth("Uint", n); err != nil {
+		return 0, err
+	}
+	if err := ba.checkBits("Uint", offset, n); err != nil {
+		return 0, err
+	}
+	var v uint64
+	for j := 0; j < n; j++ {
+		index, mask := order.bitPos(offset + j)
+		if ba[index]&mask == 0 {
+			continue
+		}
+		if order == MSB0 {
+			v |= 1 << uint(n-1-j)
+		} else {
+			v |= 1 << uint(j)
+		}
+	}
+	return v, nil
+}
+
+// Int is like Uint for a two's complement signed field.
+func (ba ByteArray) Int(offset, n int, order BitOrder) (int64, error) {
+	if err := checkFieldWidth("Int", n); err != nil {
+		return 0, err
+	}
+	v, err := ba.Uint(offset, n, order)
+	if err != nil {
+		return 0, err
+	}
+	shift := uint(64 - n)
+	return int64(v<<shift) >> shift, nil
+}
+
+// PutUint writes v into the n-bit field starting at bit offset, see Uint.
+// The other bits are left unchanged.
+func (ba ByteArray) PutUint(offset, n int, order BitOrder, v uint64) error {
+	if err := checkFieldWidth("PutUint", n); err != nil {
+		return err
+	}
+	if n < 64 && v>>uint(n) != 0 {
+		return fmt.Errorf("core.ByteArray.PutUint: %d does not fit in %d bits",
+			v, n)
+	}
+	return ba.putBits("PutUint", offset, n, order, v)
+}
+
+func (ba ByteArray) PutInt(offset, n int, order BitOrder, v int64) error {
+	if err := checkFieldWidth("PutInt", n); err != nil {
+		return err
+	}
+	shift := uint(64 - n)
+	if v<<shift>>shift != v {
+		return fmt.Errorf("core.ByteArray.PutInt: %d does not fit in %d bits",
+			v, n)
+	}
+	return ba.putBits("PutInt", offset, n, order, uint64(v))
+}
+
+func (ba ByteArray) putBits(name string, offset, n int, order BitOrder,
+	v uint64) error {
+	if err := ba.checkBits(name, offset, n); err != nil {
+		return err
+	}
+	for j := 0; j < n; j++ {
+		bit := uint(j)
+		if order == MSB0 {
+			bit = uint(n - 1 - j)
+		}
+		index, mask := order.bitPos(offset + j)
+		if v>>bit&1 != 0 {
+			ba[index] |= mask
+		} else {
+			ba[index] &^= mask
+		}
+	}
+	return nil
+}
+
+// OnesCount returns the number of set bits.
+func (ba ByteArray) OnesCount() int {
+	n := 0
+	for _, b := range ba {
+		n += bits.OnesCount8(b)
+	}
+	return n
+}
+
+func (ba ByteArray) checkMask(name string, mask []byte) error {
+	if len(mask) != len(ba) {
+		return fmt.Errorf("core.ByteArray.%s: mask has %d bytes, expect %d",
+			name, len(mask), len(ba))
+	}
+	return nil
+}
+
+// And sets ba to ba & mask in place, mask must have the same length.
+func (ba ByteArray) And(mask []byte) error {
+	if err := ba.checkMask("And", mask); err != nil {
+		return err
+	}
+	for i := range ba {
+		ba[i] &= mask[i]
+	}
+	return nil
+}
+
+func (ba ByteArray) Or(mask []byte) error {
+	if err := ba.checkMask("Or", mask); err != nil {
+		return err
+	}
+	for i := range ba {
+		ba[i] |= mask[i]
+	}
+	return nil
+}
+
+func (ba ByteArray) Xor(mask []byte) error {
+	if err := ba.checkMask("Xor", mask); err != nil {
+		return err
+	}
+	for i := range ba {
+		ba[i] ^= mask[i]
+	}
+	return nil
+}
+
+func (ba ByteArray) Not() {
+	for i := range ba {
+		ba[i] = ^ba[i]
+	}
+}
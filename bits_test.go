@@ -0,0 +1,180 @@
+package core
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+func TestBitOrderString(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal(MSB0.String(), "MSB0")
+	assert.Equal(LSB0.String(), "LSB0")
+	assert.Equal(BitOrder(2).String(), "BitOrder(2)")
+}
+
+func TestBit(t *testing.T) {
+	assert := assert.New(t)
+	ba := ByteArray{0x80, 0x01}
+	for _, c := range []struct {
+		i     int
+		order BitOrder
+		set   bool
+	}{{0, MSB0, true}, {7, MSB0, false}, {15, MSB0, true},
+		{0, LSB0, false}, {7, LSB0, true}, {8, LSB0, true}, {15, LSB0, false}} {
+		v, err := ba.Bit(c.i, c.order)
+		assert.NoError(err)
+		assert.Equal(v, c.set, "%d %v", c.i, c.order)
+	}
+	_, err := ba.Bit(16, MSB0)
+	assert.EqualError(err,
+		"core.ByteArray.Bit: bits [16, 17) out of range, have 16 bits")
+	_, err = ba.Bit(-1, MSB0)
+	assert.Error(err)
+
+	ba = ByteArray{0, 0}
+	assert.NoError(ba.SetBit(1, MSB0))
+	assert.NoError(ba.SetBit(1, LSB0))
+	assert.NoError(ba.SetBit(9, LSB0))
+	assert.Equal(ba, ByteArray{0x42, 0x02})
+	assert.NoError(ba.ClearBit(1, MSB0))
+	assert.Equal(ba, ByteArray{0x02, 0x02})
+	assert.NoError(ba.ToggleBit(8, MSB0))
+	assert.NoError(ba.ToggleBit(9, LSB0))
+	assert.Equal(ba, ByteArray{0x02, 0x80})
+	assert.Error(ba.SetBit(16, LSB0))
+	assert.Error(ba.ClearBit(16, LSB0))
+	assert.Error(ba.ToggleBit(16, LSB0))
+}
+
+func TestUint(t *testing.T) {
+	assert := assert.New(t)
+	ba := ByteArray{0x12, 0x34, 0x56}
+	v, err := ba.Uint(0, 8, MSB0)
+	assert.NoError(err)
+	assert.EqualValues(v, 0x12)
+	v, err = ba.Uint(4, 12, MSB0)
+	assert.NoError(err)
+	assert.EqualValues(v, 0x234)
+	v, err = ba.Uint(0, 24, MSB0)
+	assert.NoError(err)
+	assert.EqualValues(v, 0x123456)
+	v, err = ba.Uint(0, 24, LSB0)
+	assert.NoError(err)
+	assert.EqualValues(v, 0x563412)
+	v, err = ba.Uint(4, 12, LSB0)
+	assert.NoError(err)
+	assert.EqualValues(v, 0x341)
+	v, err = ba.Uint(3, 1, LSB0)
+	assert.NoError(err)
+	assert.EqualValues(v, 0)
+	v, err = ba.Uint(4, 1, LSB0)
+	assert.NoError(err)
+	assert.EqualValues(v, 1)
+	_, err = ba.Uint(20, 5, MSB0)
+	assert.EqualError(err,
+		"core.ByteArray.Uint: bits [20, 25) out of range, have 24 bits")
+	_, err = ba.Uint(0, 0, MSB0)
+	assert.EqualError(err, "core.ByteArray.Uint: invalid width 0")
+	_, err = ba.Uint(0, 65, MSB0)
+	assert.EqualError(err, "core.ByteArray.Uint: invalid width 65")
+
+	ff := ByteArray{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}
+	v, err = ff.Uint(0, 64, LSB0)
+	assert.NoError(err)
+	assert.EqualValues(v, uint64(math.MaxUint64))
+}
+
+func TestInt(t *testing.T) {
+	assert := assert.New(t)
+	ba := ByteArray{0xF0, 0x0F}
+	v, err := ba.Int(0, 4, MSB0)
+	assert.NoError(err)
+	assert.EqualValues(v, -1)
+	v, err = ba.Int(0, 5, MSB0)
+	assert.NoError(err)
+	assert.EqualValues(v, -2)
+	v, err = ba.Int(4, 8, MSB0)
+	assert.NoError(err)
+	assert.EqualValues(v, 0)
+	v, err = ba.Int(0, 4, LSB0)
+	assert.NoError(err)
+	assert.EqualValues(v, 0)
+	v, err = ba.Int(4, 8, LSB0)
+	assert.NoError(err)
+	assert.EqualValues(v, -1)
+	v, err = ba.Int(0, 16, LSB0)
+	assert.NoError(err)
+	assert.EqualValues(v, 0x0FF0)
+	_, err = ba.Int(0, 17, LSB0)
+	assert.Error(err)
+	_, err = ba.Int(0, 0, LSB0)
+	assert.EqualError(err, "core.ByteArray.Int: invalid width 0")
+	v, err = ByteArray{0x80, 0, 0, 0, 0, 0, 0, 0}.Int(0, 64, MSB0)
+	assert.NoError(err)
+	assert.EqualValues(v, int64(math.MinInt64))
+}
+
+func TestPutUint(t *testing.T) {
+	assert := assert.New(t)
+	ba := ByteArray{0xFF, 0x00, 0xFF}
+	assert.NoError(ba.PutUint(4, 8, MSB0, 0xA5))
+	assert.Equal(ba, ByteArray{0xFA, 0x50, 0xFF})
+	assert.NoError(ba.PutUint(4, 8, LSB0, 0xA5))
+	assert.Equal(ba, ByteArray{0x5A, 0x5A, 0xFF})
+	assert.EqualError(ba.PutUint(0, 4, MSB0, 16),
+		"core.ByteArray.PutUint: 16 does not fit in 4 bits")
+	assert.Error(ba.PutUint(20, 8, MSB0, 1))
+	assert.Error(ba.PutUint(0, 0, MSB0, 0))
+	assert.NoError(ba.PutInt(0, 4, MSB0, -2))
+	assert.Equal(ba, ByteArray{0xEA, 0x5A, 0xFF})
+	assert.NoError(ba.PutInt(16, 8, LSB0, -128))
+	assert.Equal(ba, ByteArray{0xEA, 0x5A, 0x80})
+	assert.EqualError(ba.PutInt(0, 4, MSB0, 8),
+		"core.ByteArray.PutInt: 8 does not fit in 4 bits")
+	assert.Error(ba.PutInt(0, 4, MSB0, -9))
+	assert.Error(ba.PutInt(0, 65, MSB0, 0))
+
+	// round trip at all offsets and widths
+	buf := make(ByteArray, 10)
+	for _, order := range []BitOrder{MSB0, LSB0} {
+		for n := 1; n <= 64; n++ {
+			for offset := 0; offset+n <= 80; offset += 7 {
+				expect := uint64(0x9E3779B97F4A7C15) >> uint(64-n)
+				assert.NoError(buf.PutUint(offset, n, order, expect))
+				v, err := buf.Uint(offset, n, order)
+				assert.NoError(err)
+				assert.Equal(v, expect)
+				s := -int64(expect >> 1)
+				assert.NoError(buf.PutInt(offset, n, order, s))
+				i, err := buf.Int(offset, n, order)
+				assert.NoError(err)
+				assert.Equal(i, s)
+			}
+		}
+	}
+}
+
+func TestOnesCount(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal(ByteArray{}.OnesCount(), 0)
+	assert.Equal(ByteArray{0xFF, 0x01, 0x80, 0x11}.OnesCount(), 12)
+}
+
+func TestMask(t *testing.T) {
+	assert := assert.New(t)
+	ba := ByteArray{0xF0, 0x0F}
+	assert.NoError(ba.And([]byte{0x3C, 0x3C}))
+	assert.Equal(ba, ByteArray{0x30, 0x0C})
+	assert.NoError(ba.Or([]byte{0x01, 0x80}))
+	assert.Equal(ba, ByteArray{0x31, 0x8C})
+	assert.NoError(ba.Xor([]byte{0xFF, 0x0F}))
+	assert.Equal(ba, ByteArray{0xCE, 0x83})
+	ba.Not()
+	assert.Equal(ba, ByteArray{0x31, 0x7C})
+	assert.EqualError(ba.And([]byte{1}),
+		"core.ByteArray.And: mask has 1 bytes, expect 2")
+	assert.Error(ba.Or(nil))
+	assert.Error(ba.Xor([]byte{1, 2, 3}))
+	assert.Equal(ba, ByteArray{0x31, 0x7C})
+}
@@ -2,7 +2,13 @@ package core
 
 import (
 	"bytes"
+	"encoding"
+	"encoding/binary"
+	"errors"
+	"fmt"
 	"github.com/newkedison/core/algorithm"
+	"io"
+	"math"
 	"strconv"
 )
 
@@ -70,6 +76,144 @@ func (ba *ByteArray) AppendString(s string) {
 	*ba = append(*ba, []byte(s)...)
 }
 
+func (ba *ByteArray) AppendInt8(v int8) {
+	*ba = append(*ba, byte(v))
+}
+
+func (ba *ByteArray) AppendUint16(v uint16, order binary.ByteOrder) {
+	tmp := make([]byte, 2)
+	order.PutUint16(tmp, v)
+	*ba = append(*ba, tmp...)
+}
+
+func (ba *ByteArray) AppendUint32(v uint32, order binary.ByteOrder) {
+	tmp := make([]byte, 4)
+	order.PutUint32(tmp, v)
+	*ba = append(*ba, tmp...)
+}
+
+func (ba *ByteArray) AppendUint64(v uint64, order binary.ByteOrder) {
+	tmp := make([]byte, 8)
+	order.PutUint64(tmp, v)
+	*ba = append(*ba, tmp...)
+}
+
+func (ba *ByteArray) AppendInt16(v int16, order binary.ByteOrder) {
+	ba.AppendUint16(uint16(v), order)
+}
+
+func (ba *ByteArray) AppendInt32(v int32, order binary.ByteOrder) {
+	ba.AppendUint32(uint32(v), order)
+}
+
+func (ba *ByteArray) AppendInt64(v int64, order binary.ByteOrder) {
+	ba.AppendUint64(uint64(v), order)
+}
+
+func (ba *ByteArray) AppendFloat32(v float32, order binary.ByteOrder) {
+	ba.AppendUint32(math.Float32bits(v), order)
+}
+
+func (ba *ByteArray) AppendFloat64(v float64, order binary.ByteOrder) {
+	ba.AppendUint64(math.Float64bits(v), order)
+}
+
+// AppendNumber appends v as a float64.
+func (ba *ByteArray) AppendNumber(v Number, order binary.ByteOrder) {
+	ba.AppendFloat64(float64(v), order)
+}
+
+// AppendObject appends obj in the layout of MarshalObject, nothing is
+// appended when obj fails to marshal.
+func (ba *ByteArray) AppendObject(obj encoding.BinaryMarshaler) error {
+	data, err := MarshalObject(obj)
+	if err != nil {
+		return err
+	}
+	*ba = append(*ba, data...)
+	return nil
+}
+
+// Write implements the io.Writer interface, it never fails.
+func (ba *ByteArray) Write(p []byte) (int, error) {
+	*ba = append(*ba, p...)
+	return len(p), nil
+}
+
+// WriteByte implements the io.ByteWriter interface.
+func (ba *ByteArray) WriteByte(c byte) error {
+	*ba = append(*ba, c)
+	return nil
+}
+
+// WriteString implements the io.StringWriter interface.
+func (ba *ByteArray) WriteString(s string) (int, error) {
+	*ba = append(*ba, s...)
+	return len(s), nil
+}
+
+// ReadFrom implements the io.ReaderFrom interface, it appends everything
+// read from r until io.EOF, which is not returned as an error.
+func (ba *ByteArray) ReadFrom(r io.Reader) (int64, error) {
+	const minRead = 512
+	var n int64
+	for {
+		if cap(*ba)-len(*ba) < minRead {
+			*ba = append(*ba, make([]byte, minRead)...)[:len(*ba)]
+		}
+		m, err := r.Read((*ba)[len(*ba):cap(*ba)])
+		if m < 0 {
+			panic("core.ByteArray.ReadFrom: negative count from Read")
+		}
+		*ba = (*ba)[:len(*ba)+m]
+		n += int64(m)
+		if err == io.EOF {
+			return n, nil
+		}
+		if err != nil {
+			return n, err
+		}
+	}
+}
+
+// Format implements the fmt.Formatter interface. %v is ToString, %s is the
+// bytes without the length prefix, %#v is Go syntax, the other verbs like %x,
+// %X and their flags work as for []byte.
+func (ba ByteArray) Format(f fmt.State, verb rune) {
+	var s string
+	switch {
+	case verb == 'v' && f.Flag('#'):
+		s = "core.ByteArray{" + ba.ToStringEx(false, ", ", "0x", "") + "}"
+	case verb == 'v':
+		s = ba.ToString()
+	case verb == 's':
+		s = ba.ToStringEx(false, " ", "", "")
+	default:
+		fmt.Fprintf(f, formatDirective(f, "+-# 0", verb), []byte(ba))
+		return
+	}
+	// keep width, precision and the '-' flag
+	fmt.Fprintf(f, formatDirective(f, "-", 's'), s)
+}
+
+// formatDirective rebuilds the directive of f for verb, keeping width,
+// precision and those of flags which are set.
+func formatDirective(f fmt.State, flags string, verb rune) string {
+	format := "%"
+	for _, c := range flags {
+		if f.Flag(int(c)) {
+			format += string(c)
+		}
+	}
+	if w, ok := f.Width(); ok {
+		format += strconv.Itoa(w)
+	}
+	if p, ok := f.Precision(); ok {
+		format += "." + strconv.Itoa(p)
+	}
+	return format + string(verb)
+}
+
 func (ba *ByteArray) AddCrc16() {
 	*ba = algorithm.AppendCrc16([]byte(*ba))
 }
@@ -86,6 +230,34 @@ func (ba ByteArray) Crc32() uint32 {
 	return algorithm.Crc32([]byte(ba))
 }
 
+func (ba ByteArray) Sum8() byte {
+	return algorithm.Sum8([]byte(ba))
+}
+
+func (ba ByteArray) Sum8Complement() byte {
+	return algorithm.Sum8Complement([]byte(ba))
+}
+
+func (ba ByteArray) Xor8() byte {
+	return algorithm.Xor8([]byte(ba))
+}
+
+func (ba ByteArray) LRC() byte {
+	return algorithm.LRC([]byte(ba))
+}
+
+func (ba ByteArray) Fletcher16() uint16 {
+	return algorithm.Fletcher16([]byte(ba))
+}
+
+func (ba ByteArray) Fletcher32() uint32 {
+	return algorithm.Fletcher32([]byte(ba))
+}
+
+func (ba ByteArray) Adler32() uint32 {
+	return algorithm.Adler32([]byte(ba))
+}
+
 func (ba ByteArray) Clone() ByteArray {
 	return append(ByteArray{}, ba...)
 }
@@ -106,7 +278,8 @@ func (ba ByteArray) MarshalBinary() ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
-// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface, it
+// also reads the layout of MarshalBinaryCompressed.
 func (ba *ByteArray) UnmarshalBinary(data []byte) (err error) {
 	defer SetErrorWhenNotEnoughDataErrorPanic(
 		"core.ByteArray.UnmashalBinary", &err)()
@@ -114,6 +287,21 @@ func (ba *ByteArray) UnmarshalBinary(data []byte) (err error) {
 	var l uint32
 	offset := 0
 	offset += UnmashalSimpleType(&l, data)
+	if l&compressedFlag != 0 {
+		l &^= compressedFlag
+		if l == 0 {
+			return errors.New("core.ByteArray.UnmashalBinary: " +
+				"missing compression")
+		}
+		CheckBufferSize(data, int(l), offset)
+		d, err := ByteArray(data[offset+1 : offset+int(l)]).Decompress(
+			Compression(data[offset]))
+		if err != nil {
+			return err
+		}
+		*ba = d
+		return nil
+	}
 	CheckBufferSize(data, int(l), offset)
 	ba.AssignByCopy(data[offset : offset+int(l)])
 	return nil
@@ -1,8 +1,14 @@
 package core
 
 import (
+	"encoding/binary"
+	"errors"
+	"fmt"
 	"github.com/stretchr/testify/assert"
+	"io"
+	"strings"
 	"testing"
+	"testing/iotest"
 )
 
 func TestNewByteArray(t *testing.T) {
@@ -97,6 +103,131 @@ func TestAppendString(t *testing.T) {
 	assert.Equal(ba.ToString(), "[3]41 41 41")
 }
 
+func TestAppendInt(t *testing.T) {
+	assert := assert.New(t)
+	be, le := binary.BigEndian, binary.LittleEndian
+	var ba ByteArray
+	ba.AppendInt8(-2)
+	assert.Equal(ba.ToString(), "[1]FE")
+	ba = nil
+	ba.AppendUint16(0x1234, be)
+	ba.AppendUint16(0x1234, le)
+	ba.AppendInt16(-2, be)
+	assert.Equal(ba.ToString(), "[6]12 34 34 12 FF FE")
+	ba = nil
+	ba.AppendUint32(0x12345678, be)
+	ba.AppendUint32(0x12345678, le)
+	ba.AppendInt32(-2, le)
+	assert.Equal(ba.ToString(), "[12]12 34 56 78 78 56 34 12 FE FF FF FF")
+	ba = nil
+	ba.AppendUint64(0x0102030405060708, be)
+	ba.AppendUint64(0x0102030405060708, le)
+	ba.AppendInt64(-2, be)
+	assert.Equal(ba.ToString(), "[24]01 02 03 04 05 06 07 08 "+
+		"08 07 06 05 04 03 02 01 FF FF FF FF FF FF FF FE")
+}
+
+func TestAppendFloat(t *testing.T) {
+	assert := assert.New(t)
+	be, le := binary.BigEndian, binary.LittleEndian
+	var ba ByteArray
+	ba.AppendFloat32(1.5, be)
+	ba.AppendFloat32(1.5, le)
+	assert.Equal(ba.ToString(), "[8]3F C0 00 00 00 00 C0 3F")
+	ba = nil
+	ba.AppendFloat64(1.5, be)
+	ba.AppendNumber(1.5, le)
+	assert.Equal(ba.ToString(),
+		"[16]3F F8 00 00 00 00 00 00 00 00 00 00 00 00 F8 3F")
+	r := NewByteArrayReader(ba)
+	assert.Equal(r.ReadFloat64BE(), 1.5)
+	assert.Equal(r.ReadFloat64LE(), 1.5)
+}
+
+type failMarshaler struct{}
+
+func (failMarshaler) MarshalBinary() ([]byte, error) {
+	return nil, errors.New("fail")
+}
+
+func TestAppendObject(t *testing.T) {
+	assert := assert.New(t)
+	ba := ByteArray{0xAA}
+	assert.NoError(ba.AppendObject(ByteArray{1, 2}))
+	assert.Equal(ba.ToString(), "[11]AA 06 00 00 00 02 00 00 00 01 02")
+	assert.EqualError(ba.AppendObject(failMarshaler{}), "fail")
+	assert.Len(ba, 11)
+	var dest ByteArray
+	assert.Equal(UnmarshalObject(&dest, ba[1:]), 10)
+	assert.Equal(dest, ByteArray{1, 2})
+}
+
+func TestWrite(t *testing.T) {
+	assert := assert.New(t)
+	var ba ByteArray
+	var _ io.Writer = &ba
+	var _ io.ByteWriter = &ba
+	var _ io.StringWriter = &ba
+	n, err := ba.Write([]byte{1, 2})
+	assert.NoError(err)
+	assert.Equal(n, 2)
+	assert.NoError(ba.WriteByte(3))
+	n, err = ba.WriteString("A")
+	assert.NoError(err)
+	assert.Equal(n, 1)
+	fmt.Fprintf(&ba, "%d", 5)
+	assert.Equal(ba.ToString(), "[5]01 02 03 41 35")
+}
+
+func TestReadFrom(t *testing.T) {
+	assert := assert.New(t)
+	var _ io.ReaderFrom = &ByteArray{}
+	ba := ByteArray{0xFF}
+	src := strings.Repeat("0123456789", 200)
+	n, err := ba.ReadFrom(iotest.OneByteReader(strings.NewReader(src)))
+	assert.NoError(err)
+	assert.EqualValues(n, 2000)
+	assert.Len(ba, 2001)
+	assert.Equal(string(ba[1:]), src)
+	ba = nil
+	n, err = io.Copy(&ba, iotest.DataErrReader(strings.NewReader("AB")))
+	assert.NoError(err)
+	assert.EqualValues(n, 2)
+	assert.Equal(ba, ByteArray("AB"))
+	ba = nil
+	n, err = ba.ReadFrom(iotest.TimeoutReader(strings.NewReader("ABC")))
+	assert.Equal(err, iotest.ErrTimeout)
+	assert.EqualValues(n, 3)
+	assert.Equal(ba, ByteArray("ABC"))
+}
+
+func TestFormat(t *testing.T) {
+	assert := assert.New(t)
+	ba := ByteArray{0x01, 0xAB, 0x10}
+	assert.Equal(fmt.Sprintf("%v", ba), "[3]01 AB 10")
+	assert.Equal(fmt.Sprint(ba), "[3]01 AB 10")
+	assert.Equal(fmt.Sprintf("%v", &ba), "[3]01 AB 10")
+	assert.Equal(fmt.Sprintf("%s", ba), "01 AB 10")
+	assert.Equal(fmt.Sprintf("%x", ba), "01ab10")
+	assert.Equal(fmt.Sprintf("%X", ba), "01AB10")
+	assert.Equal(fmt.Sprintf("% X", ba), "01 AB 10")
+	assert.Equal(fmt.Sprintf("%#x", ba), "0x01ab10")
+	assert.Equal(fmt.Sprintf("%# X", ba), "0X01 0XAB 0X10")
+	assert.Equal(fmt.Sprintf("%#v", ba), "core.ByteArray{0x01, 0xAB, 0x10}")
+	assert.Equal(fmt.Sprintf("%14v|", ba), "   [3]01 AB 10|")
+	assert.Equal(fmt.Sprintf("%-10s|", ba), "01 AB 10  |")
+	assert.Equal(fmt.Sprintf("%.2s", ba), "01")
+	assert.Equal(fmt.Sprintf("%10x", ba), "    01ab10")
+	for _, format := range []string{"%-10x|", "%.2x", "%08X", "% #8.1x",
+		"%d", "%+q", "%5.1o"} {
+		assert.Equal(fmt.Sprintf(format, ba), fmt.Sprintf(format, []byte(ba)),
+			format)
+	}
+	assert.Equal(fmt.Sprintf("%v", ByteArray{}), "[0]")
+	assert.Equal(fmt.Sprintf("%#v", ByteArray{}), "core.ByteArray{}")
+	assert.Equal(fmt.Sprintf("%v", []ByteArray{{1}, {2, 3}}), "[[1]01 [2]02 03]")
+}
+
 func TestAddCrc16(t *testing.T) {
 	assert := assert.New(t)
 	var ba ByteArray
@@ -125,6 +256,18 @@ func TestCrc32(t *testing.T) {
 	assert.EqualValues(ByteArray{0x10}.Crc32(), 0xCFB5FFE9)
 }
 
+func TestChecksum(t *testing.T) {
+	assert := assert.New(t)
+	ba := ByteArray("abcde")
+	assert.EqualValues(ba.Sum8(), 0xEF)
+	assert.EqualValues(ba.Sum8Complement(), 0x11)
+	assert.EqualValues(ba.Xor8(), 0x61)
+	assert.EqualValues(ByteArray{0x01, 0x03, 0x00, 0x00, 0x00, 0x01}.LRC(), 0xFB)
+	assert.EqualValues(ba.Fletcher16(), 0xC8F0)
+	assert.EqualValues(ba.Fletcher32(), 0xF04FC729)
+	assert.EqualValues(ByteArray("Wikipedia").Adler32(), 0x11E60398)
+}
+
 func TestClone(t *testing.T) {
 	assert := assert.New(t)
 	ba := ByteArray([]byte{0x01, 0x02})
@@ -0,0 +1,182 @@
+package core
+
+import (
+	"encoding/ascii85"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Encoding is a text encoding of binary data.
+type Encoding int
+
+const (
+	EncodingHex       Encoding = iota // upper case, as ToStringEx
+	EncodingBase64                    // standard alphabet, padded
+	EncodingBase64URL                 // URL and file name safe alphabet, padded
+	EncodingBase32                    // standard alphabet, padded
+	EncodingAscii85                   // without the <~ ~> delimiters
+)
+
+func (e Encoding) String() string {
+	switch e {
+	case EncodingHex:
+		return "hex"
+	case EncodingBase64:
+		return "base64"
+	case EncodingBase64URL:
+		return "base64url"
+	case EncodingBase32:
+		return "base32"
+	case EncodingAscii85:
+		return "ascii85"
+	}
+	return fmt.Sprintf("Encoding(%d)", int(e))
+}
+
+func (ba ByteArray) Encode(e Encoding) string {
+	switch e {
+	case EncodingHex:
+		return strings.ToUpper(hex.EncodeToString(ba))
+	case EncodingBase64:
+		return base64.StdEncoding.EncodeToString(ba)
+	case EncodingBase64URL:
+		return base64.URLEncoding.EncodeToString(ba)
+	case EncodingBase32:
+		return base32.StdEncoding.EncodeToString(ba)
+	case EncodingAscii85:
+		buf := make([]byte, ascii85.MaxEncodedLen(len(ba)))
+		return string(buf[:ascii85.Encode(buf, ba)])
+	}
+	panic("core.ByteArray.Encode: invalid encoding")
+}
+
+// DecodeByteArray decodes s encoded by ByteArray.Encode. Hex digits may be
+// in either case, base64 may omit the padding and Ascii85 may be enclosed
+// in <~ ~>.
+func DecodeByteArray(e Encoding, s string) (ByteArray, error) {
+	var data []byte
+	var err error
+	switch e {
+	case EncodingHex:
+		data, err = hex.DecodeString(s)
+	case EncodingBase64, EncodingBase64URL:
+		enc := base64.StdEncoding
+		if e == EncodingBase64URL {
+			enc = base64.URLEncoding
+		}
+		if len(s)%4 != 0 {
+			enc = enc.WithPadding(base64.NoPadding)
+		}
+		data, err = enc.DecodeString(s)
+	case EncodingBase32:
+		data, err = base32.StdEncoding.DecodeString(s)
+	case EncodingAscii85:
+		if strings.HasPrefix(s, "<~") && strings.HasSuffix(s, "~>") {
+			s = s[2 : len(s)-2]
+		}
+		data = make([]byte, 4*len(s))
+		var n int
+		n, _, err = ascii85.Decode(data, []byte(s), true)
+		data = data[:n]
+	default:
+		err = errors.New("invalid encoding")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("core.DecodeByteArray: %s: %s", e, err)
+	}
+	return ByteArray(data), nil
+}
+
+type upperHexEncoder struct {
+	w   io.Writer
+	buf [1024]byte
+}
+
+func (h *upperHexEncoder) Write(p []byte) (int, error) {
+	const digits = "0123456789ABCDEF"
+	n := 0
+	for len(p) > 0 {
+		chunk := p
+		if len(chunk) > len(h.buf)/2 {
+			chunk = chunk[:len(h.buf)/2]
+		}
+		for i, b := range chunk {
+			h.buf[2*i], h.buf[2*i+1] = digits[b>>4], digits[b&0xF]
+		}
+		written, err := h.w.Write(h.buf[:2*len(chunk)])
+		n += written / 2
+		if err != nil {
+			return n, err
+		}
+		p = p[len(chunk):]
+	}
+	return n, nil
+}
+
+func (h *upperHexEncoder) Close() error {
+	return nil
+}
+
+// NewEncoder returns a stream encoder writing to w, the output is the same
+// as Encode of all the data written. Close must be called to flush the last
+// partial block.
+func NewEncoder(e Encoding, w io.Writer) io.WriteCloser {
+	switch e {
+	case EncodingHex:
+		return &upperHexEncoder{w: w}
+	case EncodingBase64:
+		return base64.NewEncoder(base64.StdEncoding, w)
+	case EncodingBase64URL:
+		return base64.NewEncoder(base64.URLEncoding, w)
+	case EncodingBase32:
+		return base32.NewEncoder(base32.StdEncoding, w)
+	case EncodingAscii85:
+		return ascii85.NewEncoder(w)
+	}
+	panic("core.NewEncoder: invalid encoding")
+}
+
+// NewDecoder returns a stream decoder reading from r, the reverse of
+// NewEncoder. Unlike DecodeByteArray it requires the exact output of the
+// encoder, except that newlines are ignored.
+func NewDecoder(e Encoding, r io.Reader) io.Reader {
+	switch e {
+	case EncodingHex:
+		return hex.NewDecoder(newlineFilter{r})
+	case EncodingBase64:
+		return base64.NewDecoder(base64.StdEncoding, r)
+	case EncodingBase64URL:
+		return base64.NewDecoder(base64.URLEncoding, r)
+	case EncodingBase32:
+		return base32.NewDecoder(base32.StdEncoding, r)
+	case EncodingAscii85:
+		return ascii85.NewDecoder(r)
+	}
+	panic("core.NewDecoder: invalid encoding")
+}
+
+// newlineFilter drops '\r' and '\n' like the base64 decoder does.
+type newlineFilter struct {
+	r io.Reader
+}
+
+func (f newlineFilter) Read(p []byte) (int, error) {
+	for {
+		n, err := f.r.Read(p)
+		m := 0
+		for _, b := range p[:n] {
+			if b != '\r' && b != '\n' {
+				p[m] = b
+				m++
+			}
+		}
+		if m > 0 || err != nil {
+			return m, err
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package core
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"math/rand"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+var allEncodings = []Encoding{EncodingHex, EncodingBase64, EncodingBase64URL,
+	EncodingBase32, EncodingAscii85}
+
+func TestEncodingString(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal(EncodingHex.String(), "hex")
+	assert.Equal(EncodingBase64.String(), "base64")
+	assert.Equal(EncodingBase64URL.String(), "base64url")
+	assert.Equal(EncodingBase32.String(), "base32")
+	assert.Equal(EncodingAscii85.String(), "ascii85")
+	assert.Equal(Encoding(9).String(), "Encoding(9)")
+}
+
+func TestEncode(t *testing.T) {
+	assert := assert.New(t)
+	ba := ByteArray{0xFB, 0xFF, 0x01, 0x02}
+	assert.Equal(ba.Encode(EncodingHex), "FBFF0102")
+	assert.Equal(ba.Encode(EncodingHex), ba.ToStringEx(false, "", "", ""))
+	assert.Equal(ba.Encode(EncodingBase64), "+/8BAg==")
+	assert.Equal(ba.Encode(EncodingBase64URL), "-_8BAg==")
+	assert.Equal(ba.Encode(EncodingBase32), "7P7QCAQ=")
+	assert.Equal(ByteArray("Man is distinguished").Encode(EncodingAscii85),
+		"9jqo^BlbD-BleB1DJ+*+F(f,q")
+	assert.Equal(ByteArray{0, 0, 0, 0}.Encode(EncodingAscii85), "z")
+	for _, e := range allEncodings {
+		assert.Equal(ByteArray{}.Encode(e), "")
+	}
+	assert.Panics(func() { ba.Encode(Encoding(9)) })
+}
+
+func TestDecodeByteArray(t *testing.T) {
+	assert := assert.New(t)
+	r := rand.New(rand.NewSource(1))
+	for n := 0; n < 20; n++ {
+		ba := make(ByteArray, n)
+		r.Read(ba)
+		for _, e := range allEncodings {
+			decoded, err := DecodeByteArray(e, ba.Encode(e))
+			assert.NoError(err)
+			assert.Equal(decoded, ba, "%v %d", e, n)
+		}
+	}
+	ba, err := DecodeByteArray(EncodingHex, "fbFF")
+	assert.NoError(err)
+	assert.Equal(ba, ByteArray{0xFB, 0xFF})
+	ba, err = DecodeByteArray(EncodingBase64URL, "-_8BAg")
+	assert.NoError(err)
+	assert.Equal(ba, ByteArray{0xFB, 0xFF, 0x01, 0x02})
+	ba, err = DecodeByteArray(EncodingBase64, "+/8BAg")
+	assert.NoError(err)
+	assert.Equal(ba, ByteArray{0xFB, 0xFF, 0x01, 0x02})
+	ba, err = DecodeByteArray(EncodingAscii85, "<~9jqo^BlbD-BleB1DJ+*+F(f,q~>")
+	assert.NoError(err)
+	assert.Equal(string(ba), "Man is distinguished")
+
+	_, err = DecodeByteArray(EncodingHex, "0G")
+	assert.EqualError(err,
+		"core.DecodeByteArray: hex: encoding/hex: invalid byte: U+0047 'G'")
+	_, err = DecodeByteArray(EncodingBase64, "+/8BAg=")
+	assert.Error(err)
+	_, err = DecodeByteArray(EncodingBase64URL, "+/8BAg==")
+	assert.Error(err)
+	_, err = DecodeByteArray(EncodingBase32, "7P7QCAQ")
+	assert.Error(err)
+	_, err = DecodeByteArray(EncodingAscii85, "9jqo~")
+	assert.Error(err)
+	_, err = DecodeByteArray(Encoding(9), "")
+	assert.EqualError(err, "core.DecodeByteArray: Encoding(9): invalid encoding")
+}
+
+func TestStreamEncoder(t *testing.T) {
+	assert := assert.New(t)
+	data := make(ByteArray, 5000)
+	rand.New(rand.NewSource(2)).Read(data)
+	for _, e := range allEncodings {
+		var buf bytes.Buffer
+		w := NewEncoder(e, &buf)
+		// odd sized writes cross the block boundaries
+		for rest := data; len(rest) > 0; {
+			n := 7
+			if n > len(rest) {
+				n = len(rest)
+			}
+			_, err := w.Write(rest[:n])
+			assert.NoError(err)
+			rest = rest[n:]
+		}
+		assert.NoError(w.Close())
+		assert.Equal(buf.String(), data.Encode(e), e.String())
+
+		decoded, err := ioutil.ReadAll(NewDecoder(e,
+			iotest.HalfReader(strings.NewReader(buf.String()))))
+		assert.NoError(err)
+		assert.Equal(ByteArray(decoded), data, e.String())
+	}
+	decoded, err := ioutil.ReadAll(NewDecoder(EncodingHex,
+		strings.NewReader("0102\r\n0a0B\n")))
+	assert.NoError(err)
+	assert.Equal(decoded, []byte{1, 2, 0xA, 0xB})
+	_, err = ioutil.ReadAll(NewDecoder(EncodingHex, strings.NewReader("0G")))
+	assert.Error(err)
+	assert.Panics(func() { NewEncoder(Encoding(9), &bytes.Buffer{}) })
+	assert.Panics(func() { NewDecoder(Encoding(9), &bytes.Buffer{}) })
+}
@@ -0,0 +1,284 @@
+package core
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+)
+
+type Compression byte
+
+const (
+	CompressionNone Compression = iota
+	CompressionGzip
+	CompressionZlib
+	CompressionDeflate
+	// CompressionLZ is a fast LZ77 codec in the style of the LZ4 block
+	// format, it trades ratio for speed.
+	CompressionLZ
+)
+
+func (c Compression) String() string {
+	switch c {
+	case CompressionNone:
+		return "none"
+	case CompressionGzip:
+		return "gzip"
+	case CompressionZlib:
+		return "zlib"
+	case CompressionDeflate:
+		return "deflate"
+	case CompressionLZ:
+		return "lz"
+	}
+	return fmt.Sprintf("Compression(%d)", int(c))
+}
+
+func (ba ByteArray) Compress(c Compression) (ByteArray, error) {
+	var buf bytes.Buffer
+	var w io.WriteCloser
+	switch c {
+	case CompressionNone:
+		return ba.Clone(), nil
+	case CompressionLZ:
+		return lzCompress(ba), nil
+	case CompressionGzip:
+		w = gzip.NewWriter(&buf)
+	case CompressionZlib:
+		w = zlib.NewWriter(&buf)
+	case CompressionDeflate:
+		w, _ = flate.NewWriter(&buf, flate.DefaultCompression)
+	default:
+		return nil, fmt.Errorf("core.ByteArray.Compress: invalid compression %s", c)
+	}
+	if _, err := w.Write(ba); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return ByteArray(buf.Bytes()), nil
+}
+
+// MaxDecompressedSize limits the output of Decompress, and so of
+// UnmarshalBinary, to protect against small inputs which expand to huge
+// data.
+var MaxDecompressedSize = 64 << 20
+
+// Decompress fails when the output would exceed MaxDecompressedSize.
+func (ba ByteArray) Decompress(c Compression) (ByteArray, error) {
+	var r io.Reader
+	var err error
+	src := bytes.NewReader(ba)
+	switch c {
+	case CompressionNone:
+		return ba.Clone(), nil
+	case CompressionLZ:
+		return lzDecompress(ba)
+	case CompressionGzip:
+		r, err = gzip.NewReader(src)
+	case CompressionZlib:
+		r, err = zlib.NewReader(src)
+	case CompressionDeflate:
+		r = flate.NewReader(src)
+	default:
+		return nil, fmt.Errorf(
+			"core.ByteArray.Decompress: invalid compression %s", c)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("core.ByteArray.Decompress: %s: %s", c, err)
+	}
+	data, err := ioutil.ReadAll(io.LimitReader(r, int64(MaxDecompressedSize)+1))
+	if err != nil {
+		return nil, fmt.Errorf("core.ByteArray.Decompress: %s: %s", c, err)
+	}
+	if len(data) > MaxDecompressedSize {
+		return nil, errDecompressedTooLarge(c)
+	}
+	return ByteArray(data), nil
+}
+
+func errDecompressedTooLarge(c Compression) error {
+	return fmt.Errorf("core.ByteArray.Decompress: %s: output exceeds %d bytes",
+		c, MaxDecompressedSize)
+}
+
+// compressedFlag marks the length of the compressed binary layout.
+const compressedFlag uint32 = 1 << 31
+
+// MarshalBinaryCompressed is like MarshalBinary with the data compressed.
+// The uint32 length has bit 31 set and counts a Compression byte followed
+// by the compressed data, UnmarshalBinary detects and reads both layouts.
+func (ba ByteArray) MarshalBinaryCompressed(c Compression) ([]byte, error) {
+	data, err := ba.Compress(c)
+	if err != nil {
+		return nil, err
+	}
+	if uint64(len(data))+1 >= uint64(compressedFlag) {
+		return nil, errors.New("core.ByteArray.MarshalBinaryCompressed: " +
+			"data too large")
+	}
+	buf := new(bytes.Buffer)
+	buf.Write(MarshalSimpleType(uint32(len(data)+1) | compressedFlag))
+	buf.WriteByte(byte(c))
+	buf.Write(data)
+	return buf.Bytes(), nil
+}
+
+var errCorruptLZ = errors.New("core.ByteArray.Decompress: lz: corrupt data")
+
+const (
+	lzMinMatch  = 4
+	lzMaxOffset = 65535
+	lzHashBits  = 14
+
+	lzMaxInitialCap = 1 << 20
+)
+
+func lzHash(v uint32) uint32 {
+	return v * 2654435761 >> (32 - lzHashBits)
+}
+
+func lzAppendLength(dst []byte, n int) []byte {
+	for ; n >= 255; n -= 255 {
+		dst = append(dst, 255)
+	}
+	return append(dst, byte(n))
+}
+
+// lzAppendSequence writes the literals and a match, matchLen is 0 for the
+// last sequence, which has no match.
+func lzAppendSequence(dst, literals []byte, offset, matchLen int) []byte {
+	token := byte(0)
+	if len(literals) >= 15 {
+		token = 15 << 4
+	} else {
+		token = byte(len(literals)) << 4
+	}
+	m := matchLen - lzMinMatch
+	if matchLen > 0 {
+		if m >= 15 {
+			token |= 15
+		} else {
+			token |= byte(m)
+		}
+	}
+	dst = append(dst, token)
+	if len(literals) >= 15 {
+		dst = lzAppendLength(dst, len(literals)-15)
+	}
+	dst = append(dst, literals...)
+	if matchLen > 0 {
+		dst = append(dst, byte(offset), byte(offset>>8))
+		if m >= 15 {
+			dst = lzAppendLength(dst, m-15)
+		}
+	}
+	return dst
+}
+
+// lzCompress writes the uncompressed length as uvarint followed by
+// sequences of a token byte (4 bits literal length, 4 bits match length
+// minus 4, 15 meaning more length bytes follow), the literals, the 16-bit
+// little endian match offset and the extra match length bytes. The last
+// sequence ends after its literals.
+func lzCompress(src []byte) ByteArray {
+	head := make([]byte, binary.MaxVarintLen64)
+	dst := append(ByteArray{}, head[:binary.PutUvarint(head, uint64(len(src)))]...)
+	var table [1 << lzHashBits]int32 // position + 1 of the last occurrence
+	anchor := 0
+	for i := 0; i+lzMinMatch <= len(src); {
+		v := binary.LittleEndian.Uint32(src[i:])
+		h := lzHash(v)
+		cand := int(table[h]) - 1
+		table[h] = int32(i + 1)
+		if cand < 0 || i-cand > lzMaxOffset ||
+			binary.LittleEndian.Uint32(src[cand:]) != v {
+			i++
+			continue
+		}
+		n := lzMinMatch
+		for i+n < len(src) && src[cand+n] == src[i+n] {
+			n++
+		}
+		dst = lzAppendSequence(dst, src[anchor:i], i-cand, n)
+		i += n
+		anchor = i
+	}
+	return lzAppendSequence(dst, src[anchor:], 0, 0)
+}
+
+func lzDecompress(src []byte) (ByteArray, error) {
+	// size is not trusted, a length byte adds at most 255 bytes so the data
+	// can not grow more than that, and the buffer grows as the data is read
+	size, n := binary.Uvarint(src)
+	if n <= 0 || size > uint64(len(src))*255 {
+		return nil, errCorruptLZ
+	}
+	if size > uint64(MaxDecompressedSize) {
+		return nil, errDecompressedTooLarge(CompressionLZ)
+	}
+	capacity := size
+	if capacity > lzMaxInitialCap {
+		capacity = lzMaxInitialCap
+	}
+	dst := make(ByteArray, 0, capacity)
+	readLength := func(i, n int) (int, int, bool) {
+		if n < 15 {
+			return i, n, true
+		}
+		for {
+			if i >= len(src) {
+				return i, 0, false
+			}
+			b := src[i]
+			i++
+			n += int(b)
+			if b != 255 {
+				return i, n, true
+			}
+		}
+	}
+	for i := n; i < len(src); {
+		token := src[i]
+		i++
+		var ok bool
+		var litLen int
+		if i, litLen, ok = readLength(i, int(token>>4)); !ok ||
+			len(src)-i < litLen {
+			return nil, errCorruptLZ
+		}
+		dst = append(dst, src[i:i+litLen]...)
+		i += litLen
+		if i == len(src) {
+			break
+		}
+		if len(src)-i < 2 {
+			return nil, errCorruptLZ
+		}
+		offset := int(src[i]) | int(src[i+1])<<8
+		i += 2
+		var m int
+		if i, m, ok = readLength(i, int(token&0xF)); !ok {
+			return nil, errCorruptLZ
+		}
+		m += lzMinMatch
+		if offset == 0 || offset > len(dst) || uint64(len(dst)+m) > size {
+			return nil, errCorruptLZ
+		}
+		start := len(dst) - offset
+		for k := 0; k < m; k++ {
+			dst = append(dst, dst[start+k])
+		}
+	}
+	if uint64(len(dst)) != size {
+		return nil, errCorruptLZ
+	}
+	return dst, nil
+}
@@ -0,0 +1,138 @@
+package core
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"testing"
+)
+
+func compressSamples() []ByteArray {
+	r := rand.New(rand.NewSource(1))
+	random := make(ByteArray, 5000)
+	r.Read(random)
+	text := ByteArray(bytes.Repeat([]byte("the quick brown fox jumps "), 200))
+	return []ByteArray{
+		{},
+		{0x01},
+		{0x01, 0x02, 0x03},
+		ByteArray(bytes.Repeat([]byte{0xAA}, 1000)),
+		text,
+		random,
+		append(append(ByteArray{}, random[:100]...), text...),
+	}
+}
+
+func TestCompress(t *testing.T) {
+	assert := assert.New(t)
+	for _, c := range []Compression{CompressionNone, CompressionGzip,
+		CompressionZlib, CompressionDeflate, CompressionLZ} {
+		for _, ba := range compressSamples() {
+			data, err := ba.Compress(c)
+			assert.NoError(err, c.String())
+			ret, err := data.Decompress(c)
+			assert.NoError(err, c.String())
+			assert.Equal(len(ret), len(ba), c.String())
+			assert.True(ret.Equal(ba), c.String())
+		}
+		text := compressSamples()[4]
+		data, _ := text.Compress(c)
+		if c != CompressionNone {
+			assert.True(len(data) < len(text)/4, c.String())
+		}
+	}
+	_, err := ByteArray{1}.Compress(Compression(9))
+	assert.EqualError(err,
+		"core.ByteArray.Compress: invalid compression Compression(9)")
+	_, err = ByteArray{1}.Decompress(Compression(9))
+	assert.EqualError(err,
+		"core.ByteArray.Decompress: invalid compression Compression(9)")
+	_, err = ByteArray{1, 2, 3}.Decompress(CompressionGzip)
+	assert.Error(err)
+	assert.Equal(CompressionLZ.String(), "lz")
+	assert.Equal(CompressionGzip.String(), "gzip")
+}
+
+func TestDecompressLimit(t *testing.T) {
+	assert := assert.New(t)
+	defer func(n int) { MaxDecompressedSize = n }(MaxDecompressedSize)
+	MaxDecompressedSize = 1000
+	for _, c := range []Compression{CompressionGzip, CompressionZlib,
+		CompressionDeflate, CompressionLZ} {
+		data, _ := make(ByteArray, 1000).Compress(c)
+		ret, err := data.Decompress(c)
+		assert.NoError(err, c.String())
+		assert.Len(ret, 1000, c.String())
+		data, _ = make(ByteArray, 1001).Compress(c)
+		_, err = data.Decompress(c)
+		assert.EqualError(err, "core.ByteArray.Decompress: "+c.String()+
+			": output exceeds 1000 bytes")
+		data, _ = make(ByteArray, 1001).MarshalBinaryCompressed(c)
+		var ba ByteArray
+		assert.Error(ba.UnmarshalBinary(data), c.String())
+	}
+}
+
+func TestCompressLZ(t *testing.T) {
+	assert := assert.New(t)
+	data := lzCompress(ByteArray{})
+	assert.Equal(data, ByteArray{0x00, 0x00})
+	data = lzCompress(ByteArray{1, 2, 3})
+	assert.Equal(data, ByteArray{0x03, 0x30, 1, 2, 3})
+	// 4 literals, a match of 8 at offset 4, no trailing literals
+	data = lzCompress(ByteArray{1, 2, 3, 4, 1, 2, 3, 4, 1, 2, 3, 4})
+	assert.Equal(data, ByteArray{0x0C, 0x44, 1, 2, 3, 4, 0x04, 0x00, 0x00})
+	ret, err := data.Decompress(CompressionLZ)
+	assert.NoError(err)
+	assert.Equal(ret, ByteArray{1, 2, 3, 4, 1, 2, 3, 4, 1, 2, 3, 4})
+	// the best case ratio still fits in the size limit
+	zeros := make(ByteArray, lzMaxInitialCap*3)
+	ret, err = lzCompress(zeros).Decompress(CompressionLZ)
+	assert.NoError(err)
+	assert.True(ret.Equal(zeros))
+	for _, bad := range []ByteArray{
+		{},
+		{0x04, 0x30, 1, 2, 3},
+		{0x03, 0x40, 1, 2, 3},
+		{0x0C, 0x44, 1, 2, 3, 4, 0x05, 0x00, 0x00},
+		{0x0C, 0x44, 1, 2, 3, 4, 0x00, 0x00, 0x00},
+		{0x0C, 0x44, 1, 2, 3, 4, 0x04},
+		{0x08, 0x44, 1, 2, 3, 4, 0x04, 0x00, 0x00},
+		{0x0C, 0xF0},
+		// the declared size is larger than the data can expand to
+		{0xFF, 0xFF, 0xFF, 0xFF, 0x0F, 0x10, 0x01},
+	} {
+		_, err = bad.Decompress(CompressionLZ)
+		assert.EqualError(err, "core.ByteArray.Decompress: lz: corrupt data",
+			bad.ToString())
+	}
+}
+
+func TestMarshalBinaryCompressed(t *testing.T) {
+	assert := assert.New(t)
+	text := compressSamples()[4]
+	for _, c := range []Compression{CompressionNone, CompressionGzip,
+		CompressionZlib, CompressionDeflate, CompressionLZ} {
+		data, err := text.MarshalBinaryCompressed(c)
+		assert.NoError(err)
+		assert.Equal(data[3]&0x80, byte(0x80))
+		assert.Equal(data[4], byte(c))
+		var ba ByteArray
+		assert.NoError(ba.UnmarshalBinary(data), c.String())
+		assert.Equal(ba, text, c.String())
+	}
+	data, _ := ByteArray{1, 2, 3}.MarshalBinaryCompressed(CompressionNone)
+	assert.Equal(data, []byte{0x04, 0x00, 0x00, 0x80, 0x00, 1, 2, 3})
+	var ba ByteArray
+	assert.Error(ba.UnmarshalBinary(data[:len(data)-1]))
+	assert.EqualError(ba.UnmarshalBinary([]byte{0x00, 0x00, 0x00, 0x80}),
+		"core.ByteArray.UnmashalBinary: missing compression")
+	assert.Error(ba.UnmarshalBinary(
+		[]byte{0x04, 0x00, 0x00, 0x80, 0x09, 1, 2, 3}))
+	_, err := ByteArray{1}.MarshalBinaryCompressed(Compression(9))
+	assert.Error(err)
+	// the plain layout still works
+	data, _ = ByteArray{1, 2, 3}.MarshalBinary()
+	assert.NoError(ba.UnmarshalBinary(data))
+	assert.Equal(ba, ByteArray{1, 2, 3})
+}
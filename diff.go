@@ -0,0 +1,324 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+type DiffKind int
+
+const (
+	DiffEqual DiffKind = iota
+	DiffDelete
+	DiffInsert
+	DiffChange
+)
+
+func (k DiffKind) String() string {
+	switch k {
+	case DiffEqual:
+		return "equal"
+	case DiffDelete:
+		return "delete"
+	case DiffInsert:
+		return "insert"
+	case DiffChange:
+		return "change"
+	}
+	return fmt.Sprintf("DiffKind(%d)", int(k))
+}
+
+// DiffOp turns a[AStart:AEnd] into b[BStart:BEnd]. The range of a is empty
+// for DiffInsert and the range of b is empty for DiffDelete.
+type DiffOp struct {
+	Kind         DiffKind
+	AStart, AEnd int
+	BStart, BEnd int
+}
+
+func (op DiffOp) String() string {
+	return fmt.Sprintf("%s a[%d:%d] b[%d:%d]",
+		op.Kind, op.AStart, op.AEnd, op.BStart, op.BEnd)
+}
+
+// Diff returns the operations turning ba into b, covering both completely
+// in order. It uses Myers' O(ND) algorithm, so the number of bytes inserted
+// plus deleted is minimal. A deletion next to an insertion is reported as a
+// single DiffChange.
+func (ba ByteArray) Diff(b ByteArray) []DiffOp {
+	a := ba
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix &&
+		a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	var ops []DiffOp
+	add := func(kind DiffKind, x, y, dx, dy int) {
+		if len(ops) > 0 {
+			last := &ops[len(ops)-1]
+			if last.Kind == kind && last.AEnd == x && last.BEnd == y {
+				last.AEnd, last.BEnd = x+dx, y+dy
+				return
+			}
+		}
+		ops = append(ops, DiffOp{kind, x, x + dx, y, y + dy})
+	}
+	if prefix > 0 {
+		add(DiffEqual, 0, 0, prefix, prefix)
+	}
+	for _, e := range myers(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]) {
+		add(e.kind, prefix+e.x, prefix+e.y, e.dx, e.dy)
+	}
+	if suffix > 0 {
+		add(DiffEqual, len(a)-suffix, len(b)-suffix, suffix, suffix)
+	}
+	// merge neighbouring deletions and insertions
+	merged := ops[:0]
+	for _, op := range ops {
+		if len(merged) > 0 {
+			last := &merged[len(merged)-1]
+			if last.Kind != DiffEqual && op.Kind != DiffEqual {
+				last.Kind = DiffChange
+				last.AEnd, last.BEnd = op.AEnd, op.BEnd
+				continue
+			}
+		}
+		merged = append(merged, op)
+	}
+	return merged
+}
+
+type diffEdit struct {
+	kind         DiffKind
+	x, y, dx, dy int
+}
+
+// myers returns the edits of a shortest edit script from a to b using the
+// linear space variant of Myers' algorithm, which splits the problem at the
+// middle snake of an optimal path, so memory stays O(len(a)+len(b)) even for
+// completely different inputs.
+func myers(a, b []byte) []diffEdit {
+	var edits []diffEdit
+	var compare func(a, b []byte, x0, y0 int)
+	compare = func(a, b []byte, x0, y0 int) {
+		prefix := 0
+		for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+			prefix++
+		}
+		if prefix > 0 {
+			edits = append(edits, diffEdit{DiffEqual, x0, y0, prefix, prefix})
+		}
+		a, b, x0, y0 = a[prefix:], b[prefix:], x0+prefix, y0+prefix
+		suffix := 0
+		for suffix < len(a) && suffix < len(b) &&
+			a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+			suffix++
+		}
+		a, b = a[:len(a)-suffix], b[:len(b)-suffix]
+		switch {
+		case len(a) == 0 && len(b) > 0:
+			edits = append(edits, diffEdit{DiffInsert, x0, y0, 0, len(b)})
+		case len(b) == 0 && len(a) > 0:
+			edits = append(edits, diffEdit{DiffDelete, x0, y0, len(a), 0})
+		case len(a) > 0:
+			x, y, u, v := middleSnake(a, b)
+			compare(a[:x], b[:y], x0, y0)
+			if u > x {
+				edits = append(edits, diffEdit{DiffEqual, x0 + x, y0 + y, u - x, v - y})
+			}
+			compare(a[u:], b[v:], x0+u, y0+v)
+		}
+		if suffix > 0 {
+			edits = append(edits, diffEdit{DiffEqual, x0 + len(a), y0 + len(b),
+				suffix, suffix})
+		}
+	}
+	compare(a, b, 0, 0)
+	return edits
+}
+
+// middleSnake returns the snake from (x, y) to (u, v) in the middle of a
+// shortest edit path, found by searching forward from the start and
+// backward from the end until the two searches overlap.
+func middleSnake(a, b []byte) (x, y, u, v int) {
+	n, m := len(a), len(b)
+	delta := n - m
+	max := (n + m + 1) / 2
+	offset := max + 1
+	// forward[k] is the furthest x on diagonal k = x - y, backward[c] the
+	// furthest distance from the end on the reversed diagonal c = delta - k
+	forward := make([]int, 2*max+3)
+	backward := make([]int, 2*max+3)
+	for d := 0; d <= max; d++ {
+		for k := -d; k <= d; k += 2 {
+			if k == -d || k != d && forward[offset+k-1] < forward[offset+k+1] {
+				x = forward[offset+k+1]
+			} else {
+				x = forward[offset+k-1] + 1
+			}
+			y = x - k
+			u, v = x, y
+			for u < n && v < m && a[u] == b[v] {
+				u++
+				v++
+			}
+			forward[offset+k] = u
+			if c := delta - k; delta%2 != 0 && c >= -(d-1) && c <= d-1 &&
+				u+backward[offset+c] >= n {
+				return x, y, u, v
+			}
+		}
+		for c := -d; c <= d; c += 2 {
+			var rx int
+			if c == -d || c != d && backward[offset+c-1] < backward[offset+c+1] {
+				rx = backward[offset+c+1]
+			} else {
+				rx = backward[offset+c-1] + 1
+			}
+			ry := rx - c
+			ru, rv := rx, ry
+			for ru < n && rv < m && a[n-1-ru] == b[m-1-rv] {
+				ru++
+				rv++
+			}
+			backward[offset+c] = ru
+			if k := delta - c; delta%2 == 0 && k >= -d && k <= d &&
+				ru+forward[offset+k] >= n {
+				return n - ru, m - rv, n - rx, m - ry
+			}
+		}
+	}
+	panic("core.ByteArray.Diff: no middle snake")
+}
+
+type diffCell struct {
+	pos  int // -1 for a gap
+	b    byte
+	diff bool
+}
+
+// DiffDump renders ba and b side by side, aligned by the result of Diff.
+// Lines with differences start with "!", differing bytes are written
+// between opt.HighlightPrefix and opt.HighlightSuffix and gaps as "--".
+// Only BytesPerLine, Group, Offset, BaseOffset and the highlight markers of
+// opt are used.
+func (ba ByteArray) DiffDump(b ByteArray, opt HexdumpOptions) string {
+	perLine := opt.BytesPerLine
+	if perLine <= 0 {
+		perLine = 16
+	}
+	var left, right []diffCell
+	for _, op := range ba.Diff(b) {
+		n := op.AEnd - op.AStart
+		if op.BEnd-op.BStart > n {
+			n = op.BEnd - op.BStart
+		}
+		for i := 0; i < n; i++ {
+			l, r := diffCell{pos: -1}, diffCell{pos: -1}
+			if op.AStart+i < op.AEnd {
+				l = diffCell{op.AStart + i, ba[op.AStart+i], op.Kind != DiffEqual}
+			}
+			if op.BStart+i < op.BEnd {
+				r = diffCell{op.BStart + i, b[op.BStart+i], op.Kind != DiffEqual}
+			}
+			left, right = append(left, l), append(right, r)
+		}
+	}
+	next := func(cells []diffCell, i int, end int) int {
+		for ; i < len(cells); i++ {
+			if cells[i].pos >= 0 {
+				return cells[i].pos
+			}
+		}
+		return end
+	}
+	side := func(buf *bytes.Buffer, cells []diffCell, offset int) {
+		if opt.Offset {
+			fmt.Fprintf(buf, "%08X  ", opt.BaseOffset+offset)
+		}
+		for i := 0; i < perLine; i++ {
+			if i > 0 {
+				buf.WriteByte(' ')
+				if opt.Group > 0 && i%opt.Group == 0 {
+					buf.WriteByte(' ')
+				}
+			}
+			switch {
+			case i >= len(cells):
+				buf.WriteString("  ")
+			case cells[i].pos < 0:
+				buf.WriteString(opt.HighlightPrefix + "--" + opt.HighlightSuffix)
+			case cells[i].diff:
+				buf.WriteString(opt.HighlightPrefix +
+					ByteToHexString(cells[i].b) + opt.HighlightSuffix)
+			default:
+				buf.WriteString(ByteToHexString(cells[i].b))
+			}
+		}
+	}
+	var buf bytes.Buffer
+	for start := 0; start < len(left); start += perLine {
+		end := start + perLine
+		if end > len(left) {
+			end = len(left)
+		}
+		marker := "  "
+		for i := start; i < end; i++ {
+			if left[i].diff || right[i].diff {
+				marker = "! "
+				break
+			}
+		}
+		buf.WriteString(marker)
+		side(&buf, left[start:end], next(left, start, len(ba)))
+		buf.WriteString("  |  ")
+		side(&buf, right[start:end], next(right, start, len(b)))
+		buf.Truncate(len(bytes.TrimRight(buf.Bytes(), " ")))
+		buf.WriteByte('\n')
+	}
+	return buf.String()
+}
+
+// TestingT is the part of *testing.T used by AssertEqualByteArray.
+type TestingT interface {
+	Errorf(format string, args ...interface{})
+}
+
+// AssertEqualByteArray reports a failure to t with the diff operations and
+// a side by side dump when expected and actual differ.
+func AssertEqualByteArray(t TestingT, expected, actual ByteArray,
+	msgAndArgs ...interface{}) bool {
+	if h, ok := t.(interface{ Helper() }); ok {
+		h.Helper()
+	}
+	if bytes.Equal(expected, actual) {
+		return true
+	}
+	var msg strings.Builder
+	if len(msgAndArgs) > 0 {
+		if format, ok := msgAndArgs[0].(string); ok {
+			fmt.Fprintf(&msg, format, msgAndArgs[1:]...)
+		} else {
+			for _, a := range msgAndArgs {
+				msg.WriteString(fmt.Sprint(a))
+			}
+		}
+		msg.WriteString("\n")
+	}
+	fmt.Fprintf(&msg, "ByteArray not equal, expected %d bytes, actual %d bytes\n",
+		len(expected), len(actual))
+	for _, op := range expected.Diff(actual) {
+		if op.Kind != DiffEqual {
+			msg.WriteString(op.String() + "\n")
+		}
+	}
+	opt := HexdumpOptions{BytesPerLine: 16, Group: 8, Offset: true}
+	msg.WriteString(expected.DiffDump(actual, opt))
+	t.Errorf("%s", msg.String())
+	return false
+}
@@ -0,0 +1,201 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestDiffKindString(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal(DiffEqual.String(), "equal")
+	assert.Equal(DiffChange.String(), "change")
+	assert.Equal(DiffKind(7).String(), "DiffKind(7)")
+	assert.Equal(DiffOp{DiffInsert, 2, 2, 2, 4}.String(), "insert a[2:2] b[2:4]")
+}
+
+func TestDiff(t *testing.T) {
+	assert := assert.New(t)
+	assert.Nil(ByteArray{}.Diff(ByteArray{}))
+	assert.Equal(ByteArray{1, 2}.Diff(ByteArray{1, 2}),
+		[]DiffOp{{DiffEqual, 0, 2, 0, 2}})
+	assert.Equal(ByteArray{}.Diff(ByteArray{1, 2}),
+		[]DiffOp{{DiffInsert, 0, 0, 0, 2}})
+	assert.Equal(ByteArray{1, 2}.Diff(nil),
+		[]DiffOp{{DiffDelete, 0, 2, 0, 0}})
+	assert.Equal(ByteArray{1, 2, 3, 4}.Diff(ByteArray{1, 3, 4}),
+		[]DiffOp{{DiffEqual, 0, 1, 0, 1}, {DiffDelete, 1, 2, 1, 1},
+			{DiffEqual, 2, 4, 1, 3}})
+	assert.Equal(ByteArray{1, 3, 4}.Diff(ByteArray{1, 2, 3, 4, 5}),
+		[]DiffOp{{DiffEqual, 0, 1, 0, 1}, {DiffInsert, 1, 1, 1, 2},
+			{DiffEqual, 1, 3, 2, 4}, {DiffInsert, 3, 3, 4, 5}})
+	assert.Equal(ByteArray{1, 2, 3, 4}.Diff(ByteArray{1, 9, 9, 9, 4}),
+		[]DiffOp{{DiffEqual, 0, 1, 0, 1}, {DiffChange, 1, 3, 1, 4},
+			{DiffEqual, 3, 4, 4, 5}})
+	assert.Equal(ByteArray{1, 2}.Diff(ByteArray{3}),
+		[]DiffOp{{DiffChange, 0, 2, 0, 1}})
+}
+
+// applyDiff rebuilds b from a and checks the ops cover both in order.
+func applyDiff(a, b ByteArray, ops []DiffOp) (ByteArray, error) {
+	ret := ByteArray{}
+	x, y := 0, 0
+	for i, op := range ops {
+		if op.AStart != x || op.BStart != y {
+			return nil, fmt.Errorf("op %d not continuous", i)
+		}
+		switch op.Kind {
+		case DiffEqual:
+			if !bytes.Equal(a[op.AStart:op.AEnd], b[op.BStart:op.BEnd]) {
+				return nil, fmt.Errorf("op %d not equal", i)
+			}
+		case DiffInsert:
+			if op.AEnd != op.AStart {
+				return nil, fmt.Errorf("op %d deletes", i)
+			}
+		case DiffDelete:
+			if op.BEnd != op.BStart {
+				return nil, fmt.Errorf("op %d inserts", i)
+			}
+		}
+		ret = append(ret, b[op.BStart:op.BEnd]...)
+		x, y = op.AEnd, op.BEnd
+	}
+	if x != len(a) || y != len(b) {
+		return nil, fmt.Errorf("not complete")
+	}
+	return ret, nil
+}
+
+func TestDiffRandom(t *testing.T) {
+	assert := assert.New(t)
+	r := rand.New(rand.NewSource(1))
+	for n := 0; n < 200; n++ {
+		a := make(ByteArray, r.Intn(60))
+		for i := range a {
+			a[i] = byte(r.Intn(4))
+		}
+		b := a.Clone()
+		if n%4 == 0 {
+			// unrelated data
+			b = make(ByteArray, r.Intn(60))
+			for i := range b {
+				b[i] = byte(r.Intn(4))
+			}
+		}
+		for e := r.Intn(6); e > 0 && len(b) > 0; e-- {
+			i := r.Intn(len(b))
+			switch r.Intn(3) {
+			case 0:
+				b = append(b[:i], b[i+1:]...)
+			case 1:
+				b = append(b[:i], append(ByteArray{byte(r.Intn(4))}, b[i:]...)...)
+			default:
+				b[i] = byte(r.Intn(4))
+			}
+		}
+		ops := a.Diff(b)
+		rebuilt, err := applyDiff(a, b, ops)
+		if assert.NoError(err) {
+			assert.Equal(rebuilt, b)
+		}
+		// the edit distance is minimal, compare with dynamic programming
+		edits := 0
+		for _, op := range ops {
+			if op.Kind != DiffEqual {
+				edits += op.AEnd - op.AStart + op.BEnd - op.BStart
+			}
+		}
+		assert.Equal(edits, len(a)+len(b)-2*lcsLength(a, b))
+	}
+}
+
+func TestDiffLarge(t *testing.T) {
+	assert := assert.New(t)
+	r := rand.New(rand.NewSource(2))
+	a, b := make(ByteArray, 8192), make(ByteArray, 8192)
+	for i := range a {
+		a[i] = byte(r.Intn(128))
+		b[i] = byte(r.Intn(128)) + 128
+	}
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	ops := a.Diff(b)
+	runtime.ReadMemStats(&after)
+	assert.Equal(ops, []DiffOp{{DiffChange, 0, 8192, 0, 8192}})
+	// memory is linear in the input size
+	assert.True(after.TotalAlloc-before.TotalAlloc < 4<<20,
+		after.TotalAlloc-before.TotalAlloc)
+	r.Read(b)
+	rebuilt, err := applyDiff(a, b, a.Diff(b))
+	if assert.NoError(err) {
+		assert.Equal(rebuilt, b)
+	}
+}
+
+func lcsLength(a, b ByteArray) int {
+	dp := make([][]int, len(a)+1)
+	for i := range dp {
+		dp[i] = make([]int, len(b)+1)
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			switch {
+			case a[i-1] == b[j-1]:
+				dp[i][j] = dp[i-1][j-1] + 1
+			case dp[i-1][j] > dp[i][j-1]:
+				dp[i][j] = dp[i-1][j]
+			default:
+				dp[i][j] = dp[i][j-1]
+			}
+		}
+	}
+	return dp[len(a)][len(b)]
+}
+
+func TestDiffDump(t *testing.T) {
+	assert := assert.New(t)
+	a := ByteArray{1, 2, 3, 4, 5, 6, 7, 8, 9}
+	b := ByteArray{1, 2, 3, 4, 0xAA, 0xBB, 5, 6, 7, 8}
+	opt := HexdumpOptions{BytesPerLine: 4, Offset: true,
+		HighlightPrefix: "<", HighlightSuffix: ">"}
+	assert.Equal(a.DiffDump(b, opt),
+		"  00000000  01 02 03 04  |  00000000  01 02 03 04\n"+
+			"! 00000004  <--> <--> 05 06  |  00000004  <AA> <BB> 05 06\n"+
+			"! 00000006  07 08 <09>     |  00000008  07 08 <-->\n")
+	opt = HexdumpOptions{BytesPerLine: 8, Group: 4}
+	assert.Equal(a.DiffDump(a, opt),
+		"  01 02 03 04  05 06 07 08  |  01 02 03 04  05 06 07 08\n"+
+			"  09                        |  09\n")
+	assert.Equal(ByteArray{}.DiffDump(nil, opt), "")
+}
+
+type mockT struct {
+	msg string
+}
+
+func (m *mockT) Errorf(format string, args ...interface{}) {
+	m.msg = fmt.Sprintf(format, args...)
+}
+
+func TestAssertEqualByteArray(t *testing.T) {
+	assert := assert.New(t)
+	m := &mockT{}
+	assert.True(AssertEqualByteArray(m, ByteArray{1, 2}, ByteArray{1, 2}))
+	assert.Equal(m.msg, "")
+	assert.False(AssertEqualByteArray(m, ByteArray{1, 2, 3}, ByteArray{1, 3},
+		"frame %d", 5))
+	assert.Equal(m.msg, "frame 5\n"+
+		"ByteArray not equal, expected 3 bytes, actual 2 bytes\n"+
+		"delete a[1:2] b[1:1]\n"+
+		"! 00000000  01 02 03"+strings.Repeat(" ", 42)+"|  00000000  01 -- 03\n")
+	AssertEqualByteArray(m, ByteArray{1}, ByteArray{2})
+	assert.Equal(m.msg, "ByteArray not equal, expected 1 bytes, actual 1 bytes\n"+
+		"change a[0:1] b[0:1]\n"+
+		"! 00000000  01"+strings.Repeat(" ", 48)+"|  00000000  02\n")
+	AssertEqualByteArray(t, ByteArray{1}, ByteArray{1})
+}
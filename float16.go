@@ -0,0 +1,129 @@
+package core
+
+import (
+	"math"
+)
+
+// Float16 is the bit pattern of an IEEE 754 half-precision (binary16)
+// number: 1 sign bit, 5 exponent bits and 10 fraction bits.
+type Float16 uint16
+
+// BFloat16 is the bit pattern of a bfloat16 number: 1 sign bit, 8 exponent
+// bits and 7 fraction bits, i.e. the upper half of a float32.
+type BFloat16 uint16
+
+const (
+	float16ExpBits   = 5
+	float16FracBits  = 10
+	bfloat16ExpBits  = 8
+	bfloat16FracBits = 7
+)
+
+// floatToSmall rounds f to the nearest value of a binary format with the
+// given exponent and fraction bits (ties to even), directly from float64 so
+// there is no double rounding through float32. Overflow gives Inf, values
+// too small give subnormals or a signed zero, NaN stays a quiet NaN keeping
+// the top bits of its payload.
+func floatToSmall(f float64, expBits, fracBits uint) uint16 {
+	b := math.Float64bits(f)
+	sign := uint16(b>>63) << (expBits + fracBits)
+	exp := int(b>>52) & 0x7FF
+	frac := b & (1<<52 - 1)
+	maxExp := 1<<expBits - 1
+	switch {
+	case exp == 0x7FF && frac != 0:
+		return sign | uint16(maxExp)<<fracBits | 1<<(fracBits-1) |
+			uint16(frac>>(52-fracBits))
+	case exp == 0x7FF:
+		return sign | uint16(maxExp)<<fracBits
+	case exp == 0:
+		// Zero or a float64 subnormal, both far below the smallest
+		// subnormal of the small formats.
+		return sign
+	}
+	sig := frac | 1<<52
+	e := exp - 1023 + (1<<(expBits-1) - 1)
+	shift := 52 - fracBits
+	if e < 1 {
+		shift += uint(1 - e)
+		e = 0
+	}
+	if shift > 53 {
+		return sign
+	}
+	q := sig >> shift
+	rem := sig & (1<<shift - 1)
+	half := uint64(1) << (shift - 1)
+	if rem > half || (rem == half && q&1 == 1) {
+		q++
+	}
+	if e == 0 {
+		// Subnormal, a carry into bit fracBits gives the smallest normal
+		// number which has the same encoding.
+		return sign | uint16(q)
+	}
+	if q == 1<<(fracBits+1) {
+		q >>= 1
+		e++
+	}
+	if e >= maxExp {
+		return sign | uint16(maxExp)<<fracBits
+	}
+	return sign | uint16(e)<<fracBits | uint16(q&(1<<fracBits-1))
+}
+
+func smallToFloat(h uint16, expBits, fracBits uint) float64 {
+	sign := h >> (expBits + fracBits)
+	exp := int(h>>fracBits) & (1<<expBits - 1)
+	frac := uint64(h) & (1<<fracBits - 1)
+	bias := 1<<(expBits-1) - 1
+	var f float64
+	switch exp {
+	case 1<<expBits - 1:
+		if frac != 0 {
+			return math.Float64frombits(uint64(sign)<<63 | 0x7FF<<52 |
+				frac<<(52-fracBits) | 1<<51)
+		}
+		f = math.Inf(1)
+	case 0:
+		f = math.Ldexp(float64(frac), 1-bias-int(fracBits))
+	default:
+		f = math.Ldexp(float64(frac|1<<fracBits), exp-bias-int(fracBits))
+	}
+	if sign != 0 {
+		return -f
+	}
+	return f
+}
+
+func (v Number) ToFloat16() Float16 {
+	return Float16(floatToSmall(float64(v), float16ExpBits, float16FracBits))
+}
+
+func (v Number) ToBFloat16() BFloat16 {
+	return BFloat16(floatToSmall(float64(v), bfloat16ExpBits, bfloat16FracBits))
+}
+
+func (h Float16) ToNumber() Number {
+	return Number(smallToFloat(uint16(h), float16ExpBits, float16FracBits))
+}
+
+func (h Float16) IsNaN() bool {
+	return h&0x7C00 == 0x7C00 && h&0x03FF != 0
+}
+
+func (h Float16) IsInf() bool {
+	return h&0x7FFF == 0x7C00
+}
+
+func (h BFloat16) ToNumber() Number {
+	return Number(smallToFloat(uint16(h), bfloat16ExpBits, bfloat16FracBits))
+}
+
+func (h BFloat16) IsNaN() bool {
+	return h&0x7F80 == 0x7F80 && h&0x007F != 0
+}
+
+func (h BFloat16) IsInf() bool {
+	return h&0x7FFF == 0x7F80
+}
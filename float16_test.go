@@ -0,0 +1,136 @@
+package core
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+func TestNumberToFloat16(t *testing.T) {
+	assert := assert.New(t)
+	check := func(v float64, expect uint16) {
+		assert.EqualValues(expect, Number(v).ToFloat16(), "%v", v)
+	}
+	check(0, 0x0000)
+	check(math.Copysign(0, -1), 0x8000)
+	check(1, 0x3C00)
+	check(-2, 0xC000)
+	check(0.5, 0x3800)
+	check(0.1, 0x2E66)
+	check(1.0/3, 0x3555)
+	check(65504, 0x7BFF)
+	check(65519.99, 0x7BFF)
+	check(65520, 0x7C00)
+	check(-1e10, 0xFC00)
+	check(math.Inf(1), 0x7C00)
+	check(math.Inf(-1), 0xFC00)
+	check(math.NaN(), 0x7E00)
+	check(math.Ldexp(1, -14), 0x0400)
+	check(math.Ldexp(1, -24), 0x0001)
+	check(math.Ldexp(1, -25), 0x0000)
+	check(math.Ldexp(1.5, -25), 0x0001)
+	check(math.Ldexp(3, -25), 0x0002)
+	check(math.Ldexp(1023.5, -24), 0x0400)
+	check(math.Ldexp(1022.5, -24), 0x03FE)
+	check(math.Ldexp(1, -40), 0x0000)
+	check(-math.Ldexp(1, -40), 0x8000)
+	check(1+math.Ldexp(1, -11), 0x3C00)
+	check(1+math.Ldexp(3, -11), 0x3C02)
+	check(1+math.Ldexp(1, -11)+math.Ldexp(1, -40), 0x3C01)
+}
+
+func TestFloat16ToNumber(t *testing.T) {
+	assert := assert.New(t)
+	assert.EqualValues(Float16(0x3C00).ToNumber(), 1)
+	assert.EqualValues(Float16(0xC000).ToNumber(), -2)
+	assert.EqualValues(Float16(0x7BFF).ToNumber(), 65504)
+	assert.EqualValues(Float16(0x0400).ToNumber(), math.Ldexp(1, -14))
+	assert.EqualValues(Float16(0x0001).ToNumber(), math.Ldexp(1, -24))
+	assert.EqualValues(Float16(0x03FF).ToNumber(), math.Ldexp(1023, -24))
+	assert.True(math.Signbit(float64(Float16(0x8000).ToNumber())))
+	assert.True(math.IsInf(float64(Float16(0x7C00).ToNumber()), 1))
+	assert.True(math.IsInf(float64(Float16(0xFC00).ToNumber()), -1))
+	assert.True(math.IsNaN(float64(Float16(0x7E00).ToNumber())))
+	assert.True(math.IsNaN(float64(Float16(0xFC01).ToNumber())))
+	assert.True(Float16(0x7C01).IsNaN())
+	assert.False(Float16(0x7C00).IsNaN())
+	assert.True(Float16(0xFC00).IsInf())
+	assert.False(Float16(0x7BFF).IsInf())
+	for i := 0; i < 0x10000; i++ {
+		h := Float16(i)
+		if h.IsNaN() {
+			assert.True(h.ToNumber().ToFloat16().IsNaN())
+			continue
+		}
+		if h.ToNumber().ToFloat16() != h {
+			assert.Fail("round trip fail", "%04X", i)
+		}
+	}
+}
+
+func TestNumberToBFloat16(t *testing.T) {
+	assert := assert.New(t)
+	check := func(v float64, expect uint16) {
+		assert.EqualValues(expect, Number(v).ToBFloat16(), "%v", v)
+	}
+	check(0, 0x0000)
+	check(math.Copysign(0, -1), 0x8000)
+	check(1, 0x3F80)
+	check(-2, 0xC000)
+	check(math.Pi, 0x4049)
+	check(1.0/3, 0x3EAB)
+	check(math.MaxFloat32, 0x7F80)
+	check(3.3895313892515355e38, 0x7F7F)
+	check(math.Inf(-1), 0xFF80)
+	check(math.NaN(), 0x7FC0)
+	check(math.Ldexp(1, -126), 0x0080)
+	check(math.Ldexp(1, -133), 0x0001)
+	check(math.Ldexp(1, -134), 0x0000)
+	check(1+math.Ldexp(1, -8), 0x3F80)
+	// Rounding through float32 first would give 0x3F80 here.
+	check(1+math.Ldexp(1, -8)+math.Ldexp(1, -30), 0x3F81)
+}
+
+func TestBFloat16ToNumber(t *testing.T) {
+	assert := assert.New(t)
+	assert.EqualValues(BFloat16(0x3F80).ToNumber(), 1)
+	assert.EqualValues(BFloat16(0x4049).ToNumber(), 3.140625)
+	assert.EqualValues(BFloat16(0x0001).ToNumber(), math.Ldexp(1, -133))
+	assert.True(math.IsInf(float64(BFloat16(0xFF80).ToNumber()), -1))
+	assert.True(math.IsNaN(float64(BFloat16(0x7FC0).ToNumber())))
+	assert.True(BFloat16(0x7F81).IsNaN())
+	assert.True(BFloat16(0x7F80).IsInf())
+	for i := 0; i < 0x10000; i++ {
+		h := BFloat16(i)
+		if h.IsNaN() {
+			assert.True(h.ToNumber().ToBFloat16().IsNaN())
+			continue
+		}
+		if h.ToNumber().ToBFloat16() != h {
+			assert.Fail("round trip fail", "%04X", i)
+		}
+		f := math.Float32frombits(uint32(h) << 16)
+		if float64(f) != float64(h.ToNumber()) {
+			assert.Fail("not the upper half of float32", "%04X", i)
+		}
+	}
+}
+
+func TestNewNumberFromFloat16(t *testing.T) {
+	assert := assert.New(t)
+	assert.EqualValues(NewNumber(Float16(0x3C00)), 1)
+	assert.EqualValues(NewNumber(BFloat16(0x3F80)), 1)
+}
+
+func TestMarshalFloat16(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal(MarshalSimpleType(Number(1).ToFloat16()), []byte{0x00, 0x3C})
+	assert.Equal(MarshalSimpleType(Number(1).ToBFloat16()), []byte{0x80, 0x3F})
+	var h Float16
+	assert.Equal(UnmashalSimpleType(&h, []byte{0x00, 0xC0}), 2)
+	assert.EqualValues(h.ToNumber(), -2)
+	var b BFloat16
+	assert.Equal(UnmashalSimpleType(&b, []byte{0x49, 0x40}), 2)
+	assert.EqualValues(b.ToNumber(), 3.140625)
+	assert.Panics(func() { UnmashalSimpleType(&b, []byte{0x49}) })
+}
@@ -0,0 +1,206 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// HexdumpRange is the half-open range [Start, End) of byte indexes.
+type HexdumpRange struct {
+	Start, End int
+}
+
+type HexdumpOptions struct {
+	BytesPerLine int // 16 when <= 0
+	Group        int // bytes per group separated by an extra space, 0 for none
+	Offset       bool
+	BaseOffset   int // added to the printed offsets
+	ASCII        bool
+	// Squeeze replaces lines equal to the previous one by a single "*" like
+	// hexdump -C does. It needs Offset to tell how many lines were replaced.
+	Squeeze bool
+	// Highlights are written between HighlightPrefix and HighlightSuffix, in
+	// both the hex and the ASCII column.
+	Highlights      []HexdumpRange
+	HighlightPrefix string
+	HighlightSuffix string
+}
+
+// DefaultHexdumpOptions gives the layout of hexdump -C, highlights use ANSI
+// reverse video.
+var DefaultHexdumpOptions = HexdumpOptions{
+	BytesPerLine:    16,
+	Group:           8,
+	Offset:          true,
+	ASCII:           true,
+	Squeeze:         true,
+	HighlightPrefix: "\x1b[7m",
+	HighlightSuffix: "\x1b[0m",
+}
+
+func (ba ByteArray) Hexdump() string {
+	return ba.HexdumpEx(DefaultHexdumpOptions)
+}
+
+// HexdumpEx formats ba over multiple lines, for example
+//
+//	00000000  48 65 6C 6C 6F 2C 20 77  6F 72 6C 64 21 0A 00 01  |Hello, world!...|
+//	00000010  02                                                |.|
+//	00000011
+//
+// The last line holds the end offset and is only written with opt.Offset.
+func (ba ByteArray) HexdumpEx(opt HexdumpOptions) string {
+	perLine := opt.BytesPerLine
+	if perLine <= 0 {
+		perLine = 16
+	}
+	highlighted := func(i int) bool {
+		for _, r := range opt.Highlights {
+			if i >= r.Start && i < r.End {
+				return true
+			}
+		}
+		return false
+	}
+	var buf bytes.Buffer
+	squeezed := false
+	for start := 0; start < len(ba); start += perLine {
+		end := start + perLine
+		if end > len(ba) {
+			end = len(ba)
+		}
+		line := ba[start:end]
+		if opt.Squeeze && opt.Offset && start > 0 && end-start == perLine &&
+			bytes.Equal(line, ba[start-perLine:start]) {
+			if !squeezed {
+				buf.WriteString("*\n")
+				squeezed = true
+			}
+			continue
+		}
+		squeezed = false
+		if opt.Offset {
+			fmt.Fprintf(&buf, "%08X  ", opt.BaseOffset+start)
+		}
+		for i := 0; i < perLine; i++ {
+			if i > 0 {
+				buf.WriteByte(' ')
+				if opt.Group > 0 && i%opt.Group == 0 {
+					buf.WriteByte(' ')
+				}
+			}
+			if start+i >= end {
+				buf.WriteString("  ")
+			} else if highlighted(start + i) {
+				buf.WriteString(opt.HighlightPrefix)
+				buf.WriteString(ByteToHexString(line[i]))
+				buf.WriteString(opt.HighlightSuffix)
+			} else {
+				buf.WriteString(ByteToHexString(line[i]))
+			}
+		}
+		if opt.ASCII {
+			buf.WriteString("  |")
+			for i, b := range line {
+				if b < 0x20 || b > 0x7E {
+					b = '.'
+				}
+				if highlighted(start + i) {
+					buf.WriteString(opt.HighlightPrefix)
+					buf.WriteByte(b)
+					buf.WriteString(opt.HighlightSuffix)
+				} else {
+					buf.WriteByte(b)
+				}
+			}
+			buf.WriteByte('|')
+		}
+		buf.Truncate(len(bytes.TrimRight(buf.Bytes(), " ")))
+		buf.WriteByte('\n')
+	}
+	if opt.Offset && len(ba) > 0 {
+		fmt.Fprintf(&buf, "%08X\n", opt.BaseOffset+len(ba))
+	}
+	return buf.String()
+}
+
+var ansiEscape = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// maxParsedHexdump limits the data ParseHexdump expands "*" lines to.
+const maxParsedHexdump = 64 << 20
+
+// ParseHexdump reconstructs the bytes from the output of HexdumpEx or
+// hexdump -C. ANSI escape sequences are removed, other highlight markers are
+// not supported. When the dump has offsets they are checked,
+// and "*" lines are expanded by repeating the previous line up to the next
+// offset.
+func ParseHexdump(s string) (ByteArray, error) {
+	ret := ByteArray{}
+	var prev ByteArray
+	base := -1
+	squeezed := false
+	for n, line := range strings.Split(ansiEscape.ReplaceAllString(s, ""), "\n") {
+		fail := func(format string, args ...interface{}) error {
+			return fmt.Errorf("core.ParseHexdump: line %d: %s", n+1,
+				fmt.Sprintf(format, args...))
+		}
+		if i := strings.IndexByte(line, '|'); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if fields[0] == "*" {
+			if prev == nil || len(fields) > 1 {
+				return nil, fail("unexpected '*'")
+			}
+			squeezed = true
+			continue
+		}
+		if f := strings.TrimSuffix(fields[0], ":"); len(fields[0]) > 2 {
+			offset, err := strconv.ParseUint(f, 16, 31)
+			if err != nil {
+				return nil, fail("invalid offset %q", fields[0])
+			}
+			if base < 0 {
+				base = int(offset)
+			}
+			pos := int(offset) - base
+			if pos > maxParsedHexdump {
+				return nil, fail("offset %s beyond %d bytes", f, maxParsedHexdump)
+			}
+			if squeezed {
+				for len(ret)+len(prev) <= pos {
+					ret = append(ret, prev...)
+				}
+				squeezed = false
+			}
+			if pos != len(ret) {
+				return nil, fail("offset %s, expect %08X", f, base+len(ret))
+			}
+			fields = fields[1:]
+		} else if squeezed {
+			return nil, fail("missing offset after '*'")
+		}
+		data := make(ByteArray, 0, len(fields))
+		for _, f := range fields {
+			b, err := strconv.ParseUint(f, 16, 8)
+			if err != nil || len(f) != 2 {
+				return nil, fail("invalid byte %q", f)
+			}
+			data = append(data, byte(b))
+		}
+		if len(data) > 0 {
+			prev = data
+		}
+		ret = append(ret, data...)
+	}
+	if squeezed {
+		return nil, fmt.Errorf("core.ParseHexdump: missing offset after '*'")
+	}
+	return ret, nil
+}
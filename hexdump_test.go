@@ -0,0 +1,129 @@
+package core
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestHexdump(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal(ByteArray{}.Hexdump(), "")
+	ba := ByteArray("Hello, world!\n\x00\x01\x02")
+	assert.Equal(ba.Hexdump(),
+		"00000000  48 65 6C 6C 6F 2C 20 77  6F 72 6C 64 21 0A 00 01  |Hello, world!...|\n"+
+			"00000010  02                                                |.|\n"+
+			"00000011\n")
+	opt := HexdumpOptions{BytesPerLine: 4}
+	assert.Equal(ba[:6].HexdumpEx(opt), "48 65 6C 6C\n6F 2C\n")
+	opt = HexdumpOptions{BytesPerLine: 4, Group: 2, Offset: true,
+		BaseOffset: 0x100, ASCII: true}
+	assert.Equal(ba[:6].HexdumpEx(opt),
+		"00000100  48 65  6C 6C  |Hell|\n"+
+			"00000104  6F 2C         |o,|\n"+
+			"00000106\n")
+}
+
+func TestHexdumpHighlight(t *testing.T) {
+	assert := assert.New(t)
+	opt := HexdumpOptions{BytesPerLine: 4, ASCII: true,
+		Highlights:      []HexdumpRange{{1, 3}, {5, 6}},
+		HighlightPrefix: "<", HighlightSuffix: ">"}
+	assert.Equal(ByteArray("abcdef").HexdumpEx(opt),
+		"61 <62> <63> 64  |a<b><c>d|\n"+
+			"65 <66>        |e<f>|\n")
+	opt = DefaultHexdumpOptions
+	opt.BytesPerLine = 2
+	opt.Highlights = []HexdumpRange{{0, 1}}
+	assert.Equal(ByteArray("ab").HexdumpEx(opt),
+		"00000000  \x1b[7m61\x1b[0m 62  |\x1b[7ma\x1b[0mb|\n00000002\n")
+}
+
+func TestHexdumpSqueeze(t *testing.T) {
+	assert := assert.New(t)
+	ba := make(ByteArray, 4*5+1)
+	ba[4] = 1
+	opt := HexdumpOptions{BytesPerLine: 4, Offset: true, Squeeze: true}
+	assert.Equal(ba.HexdumpEx(opt),
+		"00000000  00 00 00 00\n"+
+			"00000004  01 00 00 00\n"+
+			"00000008  00 00 00 00\n"+
+			"*\n"+
+			"00000014  00\n"+
+			"00000015\n")
+	opt.Squeeze = false
+	assert.Equal(ByteArray{0, 0, 0, 0, 0, 0, 0, 0}.HexdumpEx(opt),
+		"00000000  00 00 00 00\n00000004  00 00 00 00\n00000008\n")
+	// without offsets the number of squeezed lines would be lost
+	opt = HexdumpOptions{BytesPerLine: 4, Squeeze: true}
+	assert.Equal(ByteArray{0, 0, 0, 0, 0, 0, 0, 0}.HexdumpEx(opt),
+		"00 00 00 00\n00 00 00 00\n")
+}
+
+func TestParseHexdump(t *testing.T) {
+	assert := assert.New(t)
+	data := make(ByteArray, 100)
+	for i := 40; i < 60; i++ {
+		data[i] = byte(i)
+	}
+	data[99] = '|'
+	opts := []HexdumpOptions{
+		DefaultHexdumpOptions,
+		{BytesPerLine: 7},
+		{BytesPerLine: 16, Squeeze: true},
+		{BytesPerLine: 8, Group: 4, Offset: true, BaseOffset: 0x1000},
+		{BytesPerLine: 16, Offset: true, Squeeze: true, ASCII: true,
+			Highlights: []HexdumpRange{{0, 50}}, HighlightPrefix: "\x1b[1;31m",
+			HighlightSuffix: "\x1b[0m"},
+	}
+	for _, opt := range opts {
+		for _, ba := range []ByteArray{{}, data[:1], data[:16], data,
+			make(ByteArray, 64)} {
+			parsed, err := ParseHexdump(ba.HexdumpEx(opt))
+			assert.NoError(err)
+			assert.Equal(parsed, ba)
+		}
+	}
+	// squeezed up to the end
+	ba := make(ByteArray, 64)
+	dump := ba.Hexdump()
+	assert.Equal(dump, "00000000  00 00 00 00 00 00 00 00  00 00 00 00 00 00 00 00"+
+		"  |................|\n*\n00000040\n")
+	parsed, err := ParseHexdump(dump)
+	assert.NoError(err)
+	assert.Equal(parsed, ba)
+	// xxd style offsets
+	parsed, err = ParseHexdump("00000000: 01 02\n00000002: 03\n")
+	assert.NoError(err)
+	assert.Equal(parsed, ByteArray{1, 2, 3})
+}
+
+func TestParseHexdumpError(t *testing.T) {
+	assert := assert.New(t)
+	_, err := ParseHexdump("00000000  01 02\n00000003  03\n")
+	assert.EqualError(err,
+		"core.ParseHexdump: line 2: offset 00000003, expect 00000002")
+	_, err = ParseHexdump("01 0G\n")
+	assert.EqualError(err, "core.ParseHexdump: line 1: invalid byte \"0G\"")
+	_, err = ParseHexdump("01 1\n")
+	assert.EqualError(err, "core.ParseHexdump: line 1: invalid byte \"1\"")
+	_, err = ParseHexdump("*\n")
+	assert.EqualError(err, "core.ParseHexdump: line 1: unexpected '*'")
+	_, err = ParseHexdump("01\n*\n02\n")
+	assert.EqualError(err, "core.ParseHexdump: line 3: missing offset after '*'")
+	_, err = ParseHexdump("00000000  01\n*\n")
+	assert.EqualError(err, "core.ParseHexdump: missing offset after '*'")
+	_, err = ParseHexdump("0000000G  01\n")
+	assert.EqualError(err, "core.ParseHexdump: line 1: invalid offset \"0000000G\"")
+	// "*" must not expand to huge data or backwards
+	_, err = ParseHexdump("00000000  00 00 00 00\n*\n7ffffff0\n")
+	assert.EqualError(err,
+		"core.ParseHexdump: line 3: offset 7ffffff0 beyond 67108864 bytes")
+	_, err = ParseHexdump("00000000  00 00\n00000002  01 00\n*\n00000002\n")
+	assert.EqualError(err,
+		"core.ParseHexdump: line 4: offset 00000002, expect 00000004")
+	_, err = ParseHexdump("00000010  00 00\n*\n00000000\n")
+	assert.Error(err)
+	parsed, err := ParseHexdump("00000000  01 02\n*\n00000006\n")
+	assert.NoError(err)
+	assert.Equal(parsed, ByteArray{1, 2, 1, 2, 1, 2})
+}
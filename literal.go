@@ -0,0 +1,194 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type LiteralOptions struct {
+	// Name is the variable name, Go literals are written as an expression
+	// when it is empty, C arrays are named "data".
+	Name string
+	// BytesPerLine wraps the output, 0 keeps everything on one line.
+	BytesPerLine int
+	// Indent of the wrapped lines, a tab when empty.
+	Indent string
+}
+
+func (opt LiteralOptions) indent() string {
+	if opt.Indent == "" {
+		return "\t"
+	}
+	return opt.Indent
+}
+
+// hexList writes ba as "0x01, 0x02", wrapped like gofmt does for composite
+// literals when opt.BytesPerLine is set, with a trailing comma if comma.
+func (ba ByteArray) hexList(opt LiteralOptions, comma bool) string {
+	if opt.BytesPerLine <= 0 || len(ba) == 0 {
+		return ba.ToStringEx(false, ", ", "0x", "")
+	}
+	var buf bytes.Buffer
+	buf.WriteString("\n")
+	for start := 0; start < len(ba); start += opt.BytesPerLine {
+		end := start + opt.BytesPerLine
+		if end > len(ba) {
+			end = len(ba)
+		}
+		buf.WriteString(opt.indent())
+		buf.WriteString(ba[start:end].ToStringEx(false, ", ", "0x", ""))
+		if end < len(ba) || comma {
+			buf.WriteString(",")
+		}
+		buf.WriteString("\n")
+	}
+	return buf.String()
+}
+
+// GoLiteral returns ba as Go source, such as []byte{0x01, 0x02} or with a
+// name var frame = []byte{0x01, 0x02}.
+func (ba ByteArray) GoLiteral(opt LiteralOptions) string {
+	s := "[]byte{" + ba.hexList(opt, true) + "}"
+	if opt.Name != "" {
+		s = "var " + opt.Name + " = " + s
+	}
+	return s
+}
+
+// CLiteral returns ba as a C array definition such as
+// uint8_t data[] = {0x01, 0x02};
+func (ba ByteArray) CLiteral(opt LiteralOptions) string {
+	name := opt.Name
+	if name == "" {
+		name = "data"
+	}
+	return "uint8_t " + name + "[] = {" + ba.hexList(opt, false) + "};"
+}
+
+// escapeByte writes b as in a Python bytes literal or Go string, printable ASCII
+// as is and everything else as \xHH.
+func escapeByte(buf *bytes.Buffer, b byte, quote byte) {
+	switch {
+	case b == '\\' || b == quote:
+		buf.WriteByte('\\')
+		buf.WriteByte(b)
+	case b == '\t':
+		buf.WriteString(`\t`)
+	case b == '\n':
+		buf.WriteString(`\n`)
+	case b == '\r':
+		buf.WriteString(`\r`)
+	case b >= 0x20 && b < 0x7F:
+		buf.WriteByte(b)
+	default:
+		buf.WriteString(`\x` + ByteToHexString(b))
+	}
+}
+
+// PythonLiteral returns ba as a Python bytes literal such as b'\x01AB', a
+// wrapped literal is a parenthesized concatenation of one literal per line.
+func (ba ByteArray) PythonLiteral(opt LiteralOptions) string {
+	literal := func(data ByteArray) string {
+		var buf bytes.Buffer
+		buf.WriteString("b'")
+		for _, b := range data {
+			escapeByte(&buf, b, '\'')
+		}
+		buf.WriteString("'")
+		return buf.String()
+	}
+	var s string
+	if opt.BytesPerLine <= 0 || len(ba) <= opt.BytesPerLine {
+		s = literal(ba)
+	} else {
+		lines := []string{}
+		for start := 0; start < len(ba); start += opt.BytesPerLine {
+			end := start + opt.BytesPerLine
+			if end > len(ba) {
+				end = len(ba)
+			}
+			lines = append(lines, literal(ba[start:end]))
+		}
+		s = "(\n" + opt.indent() + strings.Join(lines, "\n"+opt.indent()) + "\n)"
+	}
+	if opt.Name != "" {
+		s = opt.Name + " = " + s
+	}
+	return s
+}
+
+// EscapedString returns ba as the content of a Go or C string literal
+// without the quotes, such as \x01AB\n. Note that C takes all the hex digits
+// following \x, in "\x01AB" it reads a single escape.
+func (ba ByteArray) EscapedString() string {
+	var buf bytes.Buffer
+	for _, b := range ba {
+		escapeByte(&buf, b, '"')
+	}
+	return buf.String()
+}
+
+// ParseEscapedString is the reverse of EscapedString, it also accepts the
+// string enclosed in double or single quotes, the b prefix of Python, octal
+// escapes such as \0 or \177 and the escapes \a \b \f \v \' \".
+func ParseEscapedString(s string) (ByteArray, error) {
+	start := 0
+	if strings.HasPrefix(s, "b'") || strings.HasPrefix(s, `b"`) {
+		s = s[1:]
+		start = 1
+	}
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		s = s[1 : len(s)-1]
+		start++
+	} else if start > 0 {
+		return nil, fmt.Errorf(
+			"core.ParseEscapedString: missing closing quote at position %d",
+			start+len(s))
+	}
+	ret := ByteArray{}
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' {
+			ret = append(ret, s[i])
+			continue
+		}
+		pos := start + i
+		i++
+		if i >= len(s) {
+			return nil, fmt.Errorf(
+				"core.ParseEscapedString: incomplete escape at position %d", pos)
+		}
+		if j := strings.IndexByte(`abfnrtv\'"`, s[i]); j >= 0 {
+			ret = append(ret, "\a\b\f\n\r\t\v\\'\""[j])
+			continue
+		}
+		switch c := s[i]; {
+		case c == 'x':
+			if i+2 >= len(s) || !isHexDigit(s[i+1]) || !isHexDigit(s[i+2]) {
+				return nil, fmt.Errorf(
+					"core.ParseEscapedString: invalid \\x escape at position %d", pos)
+			}
+			b, _ := strconv.ParseUint(s[i+1:i+3], 16, 8)
+			ret = append(ret, byte(b))
+			i += 2
+		case c >= '0' && c <= '7':
+			j := i
+			for j < len(s) && j < i+3 && s[j] >= '0' && s[j] <= '7' {
+				j++
+			}
+			b, _ := strconv.ParseUint(s[i:j], 8, 16)
+			if b > 0xFF {
+				return nil, fmt.Errorf(
+					"core.ParseEscapedString: octal escape out of range at position %d",
+					pos)
+			}
+			ret = append(ret, byte(b))
+			i = j - 1
+		default:
+			return nil, fmt.Errorf(
+				"core.ParseEscapedString: unknown escape %q at position %d", c, pos)
+		}
+	}
+	return ret, nil
+}
@@ -0,0 +1,106 @@
+package core
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestGoLiteral(t *testing.T) {
+	assert := assert.New(t)
+	ba := ByteArray{0x01, 0xAB, 0x10}
+	assert.Equal(ba.GoLiteral(LiteralOptions{}), "[]byte{0x01, 0xAB, 0x10}")
+	assert.Equal(ByteArray{}.GoLiteral(LiteralOptions{}), "[]byte{}")
+	assert.Equal(ba.GoLiteral(LiteralOptions{Name: "frame"}),
+		"var frame = []byte{0x01, 0xAB, 0x10}")
+	assert.Equal(ba.GoLiteral(LiteralOptions{BytesPerLine: 2}),
+		"[]byte{\n\t0x01, 0xAB,\n\t0x10,\n}")
+	assert.Equal(ba.GoLiteral(LiteralOptions{BytesPerLine: 3, Indent: "  "}),
+		"[]byte{\n  0x01, 0xAB, 0x10,\n}")
+	assert.Equal(ByteArray{}.GoLiteral(LiteralOptions{BytesPerLine: 3}), "[]byte{}")
+}
+
+func TestCLiteral(t *testing.T) {
+	assert := assert.New(t)
+	ba := ByteArray{0x01, 0xAB, 0x10}
+	assert.Equal(ba.CLiteral(LiteralOptions{}),
+		"uint8_t data[] = {0x01, 0xAB, 0x10};")
+	assert.Equal(ba.CLiteral(LiteralOptions{Name: "x", BytesPerLine: 2,
+		Indent: "    "}),
+		"uint8_t x[] = {\n    0x01, 0xAB,\n    0x10\n};")
+	parsed, err := ParseByteArray(ba.CLiteral(LiteralOptions{BytesPerLine: 2}))
+	assert.NoError(err)
+	assert.Equal(parsed, ba)
+	parsed, err = ParseByteArray(ba.GoLiteral(LiteralOptions{BytesPerLine: 2}))
+	assert.NoError(err)
+	assert.Equal(parsed, ba)
+}
+
+func TestPythonLiteral(t *testing.T) {
+	assert := assert.New(t)
+	ba := ByteArray("\x01AB'\\\n")
+	assert.Equal(ba.PythonLiteral(LiteralOptions{}), `b'\x01AB\'\\\n'`)
+	assert.Equal(ByteArray{}.PythonLiteral(LiteralOptions{}), "b''")
+	assert.Equal(ba.PythonLiteral(LiteralOptions{Name: "frame"}),
+		`frame = b'\x01AB\'\\\n'`)
+	assert.Equal(ba.PythonLiteral(LiteralOptions{BytesPerLine: 4, Indent: "    "}),
+		"(\n    b'\\x01AB\\''\n    b'\\\\\\n'\n)")
+	assert.Equal(ba.PythonLiteral(LiteralOptions{BytesPerLine: 6}),
+		ba.PythonLiteral(LiteralOptions{}))
+}
+
+func TestEscapedString(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal(ByteArray("\x01AB").EscapedString(), `\x01AB`)
+	assert.Equal(ByteArray("a\"b\\c'\t\r\n\x7F\xFF").EscapedString(),
+		`a\"b\\c'\t\r\n\x7F\xFF`)
+	assert.Equal(ByteArray{}.EscapedString(), "")
+	all := make(ByteArray, 256)
+	for i := range all {
+		all[i] = byte(i)
+	}
+	parsed, err := ParseEscapedString(all.EscapedString())
+	assert.NoError(err)
+	assert.Equal(parsed, all)
+	parsed, err = ParseEscapedString(`"` + all.EscapedString() + `"`)
+	assert.NoError(err)
+	assert.Equal(parsed, all)
+}
+
+func TestParseEscapedString(t *testing.T) {
+	assert := assert.New(t)
+	for in, expect := range map[string]string{
+		``:                  "",
+		`abc`:               "abc",
+		`\x01AB`:            "\x01AB",
+		`\xfF`:              "\xFF",
+		`"\x01AB"`:          "\x01AB",
+		`'it\'s'`:           "it's",
+		`b'\x01\x02'`:       "\x01\x02",
+		`b"\x01"`:           "\x01",
+		`\a\b\f\n\r\t\v`:    "\a\b\f\n\r\t\v",
+		`\\\'\"`:            "\\'\"",
+		`\0\00\000\0000\18`: "\x00\x00\x00\x000\x018",
+		`\177\377`:          "\x7F\xFF",
+	} {
+		parsed, err := ParseEscapedString(in)
+		if assert.NoError(err, in) {
+			assert.Equal(string(parsed), expect, in)
+		}
+	}
+	for in, msg := range map[string]string{
+		`ab\`:     "incomplete escape at position 2",
+		`\x1`:     "invalid \\x escape at position 0",
+		`a\xG1`:   "invalid \\x escape at position 1",
+		`"\400"`:  "octal escape out of range at position 1",
+		`ab\q`:    "unknown escape 'q' at position 2",
+		`b'\x01`:  "missing closing quote at position 6",
+		`b'\x0G'`: "invalid \\x escape at position 2",
+	} {
+		_, err := ParseEscapedString(in)
+		assert.EqualError(err, "core.ParseEscapedString: "+msg, in)
+	}
+	parsed, err := ParseEscapedString(ByteArray("\x01AB'").PythonLiteral(
+		LiteralOptions{}))
+	assert.NoError(err)
+	assert.Equal(parsed, ByteArray("\x01AB'"))
+}
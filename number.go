@@ -40,21 +40,25 @@ func NewNumber(d interface{}) Number {
 			panic("Assign an int64 bigger than " +
 				strconv.FormatInt(MaxIntNumber, 10) +
 				" will lost significant digits, " +
-				"used float64 instead")
+				"use float64 or WideNumber instead")
 		}
 		if v < MinIntNumber {
 			panic("Assign an int64 smaller than " +
 				strconv.FormatInt(MinIntNumber, 10) +
 				" will lost significant digits, " +
-				"used float64 instead")
+				"use float64 or WideNumber instead")
 		}
 	case uint64:
 		if v > uint64(MaxIntNumber) {
 			panic("Assign an uint64 bigger than " +
 				strconv.FormatUint(MaxUintNumber, 10) +
 				" will lost significant digits, " +
-				"used float64 instead")
+				"use float64 or WideNumber instead")
 		}
+	case Float16:
+		return v.ToNumber()
+	case BFloat16:
+		return v.ToNumber()
 	}
 	if reflect.TypeOf(d).ConvertibleTo(NumberType) {
 		return reflect.ValueOf(d).Convert(NumberType).Interface().(Number)
@@ -0,0 +1,143 @@
+package core
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseError reports where and why ParseByteArray failed, Pos is the byte
+// offset in the input.
+type ParseError struct {
+	Pos int
+	Msg string
+}
+
+func (e ParseError) Error() string {
+	return fmt.Sprintf("core.ParseByteArray: %s at position %d", e.Msg, e.Pos)
+}
+
+func isHexDigit(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F'
+}
+
+func isAlnum(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// ParseByteArray parses the text forms of a ByteArray, the reverse of
+// ToStringEx:
+//
+//	[3]01 02 03
+//	0x01,0x02,0x03
+//	01h 02h 03h
+//	010203
+//	unsigned char buf[] = {0x01, 0x02, 0x03};
+//	[]byte{0x1, 0x2, 0x3}
+//
+// Bytes are one or two hex digits with an optional 0x prefix or h suffix, a
+// run of digits without prefix or suffix holds one byte per two digits. Any
+// character other than a letter or a digit separates bytes. An optional
+// [n] length prefix must match the number of bytes. For array literals only
+// the part between the braces is parsed, and there bytes without the 0x
+// prefix are decimal as in C and Go.
+func ParseByteArray(s string) (ByteArray, error) {
+	start, end := 0, len(s)
+	braces := false
+	if opening := strings.IndexByte(s, '{'); opening >= 0 {
+		closing := strings.LastIndexByte(s, '}')
+		if closing < opening {
+			return nil, ParseError{len(s), "missing '}'"}
+		}
+		if i := strings.IndexFunc(s[closing+1:], func(r rune) bool {
+			return r != ';' && !strings.ContainsRune(" \t\r\n", r)
+		}); i >= 0 {
+			return nil, ParseError{closing + 1 + i, "unexpected text after '}'"}
+		}
+		start, end = opening+1, closing
+		braces = true
+	}
+	expect := -1
+	i := start
+	for i < end && strings.IndexByte(" \t\r\n", s[i]) >= 0 {
+		i++
+	}
+	lenPos := i
+	if !braces && i < end && s[i] == '[' {
+		j := strings.IndexByte(s[i:end], ']')
+		if j < 0 {
+			return nil, ParseError{i, "missing ']'"}
+		}
+		n, err := strconv.Atoi(s[i+1 : i+j])
+		if err != nil || n < 0 {
+			return nil, ParseError{i + 1, "invalid length"}
+		}
+		expect = n
+		i += j + 1
+	}
+	ret := ByteArray{}
+	for i < end {
+		c := s[i]
+		if !isAlnum(c) {
+			i++
+			continue
+		}
+		tokenPos := i
+		prefixed := c == '0' && i+1 < end && (s[i+1] == 'x' || s[i+1] == 'X')
+		if prefixed {
+			i += 2
+		} else if braces {
+			j := i
+			for j < end && s[j] >= '0' && s[j] <= '9' {
+				j++
+			}
+			if j < end && isAlnum(s[j]) {
+				return nil, ParseError{j, fmt.Sprintf("invalid character %q", s[j])}
+			}
+			b, err := strconv.ParseUint(s[i:j], 10, 8)
+			if err != nil {
+				return nil, ParseError{tokenPos, "byte value out of range"}
+			}
+			ret = append(ret, byte(b))
+			i = j
+			continue
+		}
+		j := i
+		for j < end && isHexDigit(s[j]) {
+			j++
+		}
+		digits := s[i:j]
+		suffixed := j < end && (s[j] == 'h' || s[j] == 'H')
+		if suffixed {
+			if prefixed {
+				return nil, ParseError{j, "invalid character 'h'"}
+			}
+			j++
+		}
+		if j < end && isAlnum(s[j]) {
+			return nil, ParseError{j, fmt.Sprintf("invalid character %q", s[j])}
+		}
+		switch {
+		case len(digits) == 0:
+			return nil, ParseError{i, "missing hex digits"}
+		case (prefixed || suffixed) && len(digits) > 2:
+			return nil, ParseError{tokenPos, "byte value out of range"}
+		case len(digits) > 2 && len(digits)%2 == 1:
+			return nil, ParseError{tokenPos, "odd number of hex digits"}
+		case len(digits) <= 2:
+			b, _ := strconv.ParseUint(digits, 16, 8)
+			ret = append(ret, byte(b))
+		default:
+			for k := 0; k < len(digits); k += 2 {
+				b, _ := strconv.ParseUint(digits[k:k+2], 16, 8)
+				ret = append(ret, byte(b))
+			}
+		}
+		i = j
+	}
+	if expect >= 0 && expect != len(ret) {
+		return nil, ParseError{lenPos, fmt.Sprintf(
+			"length prefix %d does not match %d bytes", expect, len(ret))}
+	}
+	return ret, nil
+}
@@ -0,0 +1,75 @@
+package core
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func testParseByteArray(assert *assert.Assertions, in string, expect []byte) {
+	ba, err := ParseByteArray(in)
+	if assert.NoError(err, in) {
+		assert.Equal([]byte(ba), expect, in)
+	}
+}
+
+func testParseByteArrayError(assert *assert.Assertions, in string,
+	pos int, msg string) {
+	_, err := ParseByteArray(in)
+	assert.Equal(err, ParseError{pos, msg}, in)
+}
+
+func TestParseByteArray(t *testing.T) {
+	assert := assert.New(t)
+	testParseByteArray(assert, "", []byte{})
+	testParseByteArray(assert, "[0]", []byte{})
+	testParseByteArray(assert, "[3]01 02 03", []byte{1, 2, 3})
+	testParseByteArray(assert, "  [3] 01  02\t03\n", []byte{1, 2, 3})
+	testParseByteArray(assert, "0x01,0x02,0xfF", []byte{1, 2, 0xFF})
+	testParseByteArray(assert, "0X1, 0xA", []byte{1, 0xA})
+	testParseByteArray(assert, "01h 02H 0Ah", []byte{1, 2, 0xA})
+	testParseByteArray(assert, "010203", []byte{1, 2, 3})
+	testParseByteArray(assert, "AB-CD:EF|01;23", []byte{0xAB, 0xCD, 0xEF, 1, 0x23})
+	testParseByteArray(assert, "7", []byte{7})
+	testParseByteArray(assert, "{0x01, 0x02, 0x03,}", []byte{1, 2, 3})
+	testParseByteArray(assert,
+		"static const unsigned char buf[3] = {\n  0x01, 0x02,\n  0x03\n};\n",
+		[]byte{1, 2, 3})
+	testParseByteArray(assert, "[]byte{0x1, 0x2}", []byte{1, 2})
+	testParseByteArray(assert, "{}", []byte{})
+	testParseByteArray(assert, "{1, 2, 10}", []byte{1, 2, 10})
+	testParseByteArray(assert, "[]byte{10, 255}", []byte{10, 255})
+	testParseByteArray(assert, "uint8_t a[] = {0x10, 10, 0};", []byte{0x10, 10, 0})
+	for _, ba := range []ByteArray{{}, {0}, {1, 2, 0xFE, 0xFF}} {
+		testParseByteArray(assert, ba.ToString(), ba)
+		testParseByteArray(assert, ba.ToStringEx(true, ",", "0x", ""), ba)
+		testParseByteArray(assert, ba.ToStringEx(false, "", "", ""), ba)
+		testParseByteArray(assert, ba.ToStringEx(false, " ", "", "H"), ba)
+	}
+}
+
+func TestParseByteArrayError(t *testing.T) {
+	assert := assert.New(t)
+	testParseByteArrayError(assert, "01 0Z", 4, "invalid character 'Z'")
+	testParseByteArrayError(assert, "01 G1", 3, "invalid character 'G'")
+	testParseByteArrayError(assert, "01 0x", 5, "missing hex digits")
+	testParseByteArrayError(assert, "01 0x,", 5, "missing hex digits")
+	testParseByteArrayError(assert, "01 0x123", 3, "byte value out of range")
+	testParseByteArrayError(assert, "123h", 0, "byte value out of range")
+	testParseByteArrayError(assert, "0x12h", 4, "invalid character 'h'")
+	testParseByteArrayError(assert, "00 01 02 123", 9, "odd number of hex digits")
+	testParseByteArrayError(assert, "[2]01", 0,
+		"length prefix 2 does not match 1 bytes")
+	testParseByteArrayError(assert, " [x]01", 2, "invalid length")
+	testParseByteArrayError(assert, "[-1]", 1, "invalid length")
+	testParseByteArrayError(assert, "[2 01 02", 0, "missing ']'")
+	testParseByteArrayError(assert, "{0x01, 0x02", 11, "missing '}'")
+	testParseByteArrayError(assert, "{0x01} 02", 7, "unexpected text after '}'")
+	testParseByteArrayError(assert, "{0x01, 0xZ1}", 9, "invalid character 'Z'")
+	testParseByteArrayError(assert, "{1, 256}", 4, "byte value out of range")
+	testParseByteArrayError(assert, "{1, 0A}", 5, "invalid character 'A'")
+	testParseByteArrayError(assert, "{1, FF}", 4, "invalid character 'F'")
+	testParseByteArrayError(assert, "{1, 01h}", 6, "invalid character 'h'")
+	_, err := ParseByteArray("01 0Z")
+	assert.EqualError(err,
+		"core.ParseByteArray: invalid character 'Z' at position 4")
+}
@@ -0,0 +1,208 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// QFormat describes a fixed-point number in Qm.n notation: IntBits integer
+// bits and FracBits fractional bits, plus a sign bit when Signed is set.
+// Q15 is QFormat{0, 15, true} and occupies 16 bits, UQ8.8 is
+// QFormat{8, 8, false}.
+type QFormat struct {
+	IntBits  int
+	FracBits int
+	Signed   bool
+}
+
+var (
+	Q7   = QFormat{0, 7, true}
+	Q15  = QFormat{0, 15, true}
+	Q31  = QFormat{0, 31, true}
+	Q63  = QFormat{0, 63, true}
+	UQ8  = QFormat{0, 8, false}
+	UQ16 = QFormat{0, 16, false}
+	UQ32 = QFormat{0, 32, false}
+)
+
+func NewQFormat(intBits, fracBits int, signed bool) (QFormat, error) {
+	f := QFormat{intBits, fracBits, signed}
+	if err := f.Validate(); err != nil {
+		return QFormat{}, err
+	}
+	return f, nil
+}
+
+func (f QFormat) Validate() error {
+	if f.IntBits < 0 || f.FracBits < 0 {
+		return errors.New("core.QFormat: negative bit count")
+	}
+	if f.Bits() < 1 || f.Bits() > 64 {
+		return fmt.Errorf("core.QFormat: %s needs %d bits, must be 1..64",
+			f.String(), f.Bits())
+	}
+	return nil
+}
+
+// Bits returns the total width including the sign bit.
+func (f QFormat) Bits() int {
+	if f.Signed {
+		return f.IntBits + f.FracBits + 1
+	}
+	return f.IntBits + f.FracBits
+}
+
+// Size returns the number of bytes used on the wire, which is the smallest
+// of 1, 2, 4 or 8 able to hold Bits.
+func (f QFormat) Size() int {
+	switch bits := f.Bits(); {
+	case bits <= 8:
+		return 1
+	case bits <= 16:
+		return 2
+	case bits <= 32:
+		return 4
+	}
+	return 8
+}
+
+func (f QFormat) String() string {
+	prefix := "Q"
+	if !f.Signed {
+		prefix = "UQ"
+	}
+	return fmt.Sprintf("%s%d.%d", prefix, f.IntBits, f.FracBits)
+}
+
+// Resolution returns the value of the least significant bit.
+func (f QFormat) Resolution() Number {
+	return Number(math.Ldexp(1, -f.FracBits))
+}
+
+func (f QFormat) maxRaw() int64 {
+	if f.Signed {
+		return int64(uint64(1)<<uint(f.Bits()-1) - 1)
+	}
+	return 0
+}
+
+func (f QFormat) minRaw() int64 {
+	if f.Signed {
+		return -int64(uint64(1) << uint(f.Bits()-1))
+	}
+	return 0
+}
+
+func (f QFormat) maxRawUnsigned() uint64 {
+	return math.MaxUint64 >> uint(64-f.Bits())
+}
+
+func (f QFormat) Max() Number {
+	if f.Signed {
+		return Number(math.Ldexp(float64(f.maxRaw()), -f.FracBits))
+	}
+	return Number(math.Ldexp(float64(f.maxRawUnsigned()), -f.FracBits))
+}
+
+func (f QFormat) Min() Number {
+	return Number(math.Ldexp(float64(f.minRaw()), -f.FracBits))
+}
+
+// FromNumber converts v to the raw bit pattern of f, rounding to the nearest
+// step (half away from zero). Values out of range saturate to Min or Max and
+// NaN converts to 0; in both cases saturated is true. For signed formats the
+// raw value is two's complement truncated to Bits, so it can be written to
+// the wire as is.
+func (f QFormat) FromNumber(v Number) (raw uint64, saturated bool) {
+	if math.IsNaN(float64(v)) {
+		return 0, true
+	}
+	scaled := math.Round(math.Ldexp(float64(v), f.FracBits))
+	if f.Signed {
+		// 2^(Bits-1) is exactly representable, compare against it instead of
+		// maxRaw which may round up when converted to float64.
+		limit := math.Ldexp(1, f.Bits()-1)
+		var i int64
+		switch {
+		case scaled >= limit:
+			i, saturated = f.maxRaw(), true
+		case scaled < -limit:
+			i, saturated = f.minRaw(), true
+		default:
+			i = int64(scaled)
+		}
+		return uint64(i) & f.maxRawUnsigned(), saturated
+	}
+	limit := math.Ldexp(1, f.Bits())
+	switch {
+	case scaled >= limit:
+		return f.maxRawUnsigned(), true
+	case scaled < 0:
+		return 0, true
+	}
+	return uint64(scaled), false
+}
+
+// ToNumber converts the raw bit pattern of f back to a Number. Bits above
+// Bits are ignored and the sign is extended for signed formats.
+func (f QFormat) ToNumber(raw uint64) Number {
+	raw &= f.maxRawUnsigned()
+	if f.Signed {
+		shift := uint(64 - f.Bits())
+		return Number(math.Ldexp(float64(int64(raw<<shift)>>shift), -f.FracBits))
+	}
+	return Number(math.Ldexp(float64(raw), -f.FracBits))
+}
+
+// MarshalQNumber serializes v as a fixed-point value of format f using
+// f.Size() bytes in the default byte order, sign extended to the full width
+// for signed formats. It panics if f is invalid, like MarshalSimpleType does
+// for unknown types.
+func MarshalQNumber(v Number, f QFormat) []byte {
+	if err := f.Validate(); err != nil {
+		panic(err.Error())
+	}
+	raw, _ := f.FromNumber(v)
+	if f.Signed {
+		shift := uint(64 - f.Bits())
+		raw = uint64(int64(raw<<shift) >> shift)
+	}
+	switch f.Size() {
+	case 1:
+		return MarshalSimpleType(byte(raw))
+	case 2:
+		return MarshalSimpleType(uint16(raw))
+	case 4:
+		return MarshalSimpleType(uint32(raw))
+	}
+	return MarshalSimpleType(raw)
+}
+
+// UnmarshalQNumber is the reverse of MarshalQNumber, it returns the number
+// of bytes consumed and panics with NotEnoughDataError if data is too short.
+func UnmarshalQNumber(p *Number, f QFormat, data []byte) int {
+	if err := f.Validate(); err != nil {
+		panic(err.Error())
+	}
+	var raw uint64
+	var n int
+	switch f.Size() {
+	case 1:
+		var v byte
+		n = UnmashalSimpleType(&v, data)
+		raw = uint64(v)
+	case 2:
+		var v uint16
+		n = UnmashalSimpleType(&v, data)
+		raw = uint64(v)
+	case 4:
+		var v uint32
+		n = UnmashalSimpleType(&v, data)
+		raw = uint64(v)
+	default:
+		n = UnmashalSimpleType(&raw, data)
+	}
+	*p = f.ToNumber(raw)
+	return n
+}
@@ -0,0 +1,139 @@
+package core
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+func TestNewQFormat(t *testing.T) {
+	assert := assert.New(t)
+	f, err := NewQFormat(3, 12, true)
+	assert.NoError(err)
+	assert.Equal(f.Bits(), 16)
+	assert.Equal(f.Size(), 2)
+	assert.Equal(f.String(), "Q3.12")
+	_, err = NewQFormat(-1, 12, true)
+	assert.Error(err)
+	_, err = NewQFormat(0, 0, false)
+	assert.EqualError(err, "core.QFormat: UQ0.0 needs 0 bits, must be 1..64")
+	_, err = NewQFormat(32, 32, true)
+	assert.EqualError(err, "core.QFormat: Q32.32 needs 65 bits, must be 1..64")
+	_, err = NewQFormat(32, 32, false)
+	assert.NoError(err)
+}
+
+func TestQFormatSize(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal(Q7.Size(), 1)
+	assert.Equal(Q15.Size(), 2)
+	assert.Equal(QFormat{4, 19, true}.Size(), 4)
+	assert.Equal(Q31.Size(), 4)
+	assert.Equal(QFormat{16, 16, true}.Size(), 8)
+	assert.Equal(Q63.Size(), 8)
+	assert.Equal(UQ8.Size(), 1)
+	assert.Equal(UQ16.Size(), 2)
+	assert.Equal(UQ32.Size(), 4)
+}
+
+func TestQFormatRange(t *testing.T) {
+	assert := assert.New(t)
+	assert.EqualValues(Q15.Min(), -1)
+	assert.EqualValues(Q15.Max(), 1-math.Ldexp(1, -15))
+	assert.EqualValues(Q15.Resolution(), math.Ldexp(1, -15))
+	assert.EqualValues(UQ8.Min(), 0)
+	assert.EqualValues(UQ8.Max(), 255.0/256)
+	assert.EqualValues(QFormat{7, 8, true}.Min(), -128)
+	assert.EqualValues(QFormat{7, 8, true}.Max(), 128-1.0/256)
+	assert.EqualValues(QFormat{8, 0, false}.Max(), 255)
+}
+
+func TestQFormatFromNumber(t *testing.T) {
+	assert := assert.New(t)
+	check := func(f QFormat, v Number, raw uint64, saturated bool) {
+		r, s := f.FromNumber(v)
+		assert.Equal(raw, r, "%s %v", f, v)
+		assert.Equal(saturated, s, "%s %v", f, v)
+	}
+	check(Q15, 0, 0x0000, false)
+	check(Q15, 0.5, 0x4000, false)
+	check(Q15, -0.5, 0xC000, false)
+	check(Q15, -1, 0x8000, false)
+	check(Q15, 1, 0x7FFF, true)
+	check(Q15, -1.5, 0x8000, true)
+	check(Q15, 100, 0x7FFF, true)
+	check(Q15, Number(math.Inf(1)), 0x7FFF, true)
+	check(Q15, Number(math.Inf(-1)), 0x8000, true)
+	check(Q15, Number(math.NaN()), 0, true)
+	check(Q15, Number(math.Ldexp(1, -16)), 0x0001, false)
+	check(Q15, Number(-math.Ldexp(1, -16)), 0xFFFF, false)
+	check(Q15, Number(math.Ldexp(1, -17)), 0x0000, false)
+	check(Q31, 0.25, 0x20000000, false)
+	check(Q31, -0.25, 0xE0000000, false)
+	check(Q63, -1, 0x8000000000000000, false)
+	check(Q63, 1, 0x7FFFFFFFFFFFFFFF, true)
+	check(QFormat{7, 8, true}, 1.5, 0x0180, false)
+	check(QFormat{7, 8, true}, -1.5, 0xFE80, false)
+	check(UQ8, 0.5, 0x80, false)
+	check(UQ8, 1, 0xFF, true)
+	check(UQ8, -0.1, 0x00, true)
+	check(QFormat{8, 8, false}, 255.99609375, 0xFFFF, false)
+	check(QFormat{32, 32, false}, 1.5, 0x0000000180000000, false)
+	check(QFormat{64, 0, false}, Number(math.Ldexp(1, 64)), math.MaxUint64, true)
+}
+
+func TestQFormatToNumber(t *testing.T) {
+	assert := assert.New(t)
+	assert.EqualValues(Q15.ToNumber(0x4000), 0.5)
+	assert.EqualValues(Q15.ToNumber(0xC000), -0.5)
+	assert.EqualValues(Q15.ToNumber(0x8000), -1)
+	assert.EqualValues(Q15.ToNumber(0xFFFF), -math.Ldexp(1, -15))
+	assert.EqualValues(Q15.ToNumber(0x12344000), 0.5)
+	assert.EqualValues(Q31.ToNumber(0xE0000000), -0.25)
+	assert.EqualValues(Q63.ToNumber(0x8000000000000000), -1)
+	assert.EqualValues(QFormat{7, 8, true}.ToNumber(0xFE80), -1.5)
+	assert.EqualValues(QFormat{4, 3, false}.ToNumber(0xFF), 15.875)
+	assert.EqualValues(UQ32.ToNumber(0x80000000), 0.5)
+	for _, f := range []QFormat{Q7, Q15, Q31, {3, 12, true}, {2, 5, false}} {
+		for _, v := range []Number{0, 0.125, -0.125, 0.75, -0.75} {
+			if v < f.Min() {
+				continue
+			}
+			raw, saturated := f.FromNumber(v)
+			assert.False(saturated)
+			assert.EqualValues(f.ToNumber(raw), v, "%s %v", f, v)
+		}
+	}
+}
+
+func TestMarshalQNumber(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal(MarshalQNumber(0.5, Q7), []byte{0x40})
+	assert.Equal(MarshalQNumber(-0.5, Q15), []byte{0x00, 0xC0})
+	assert.Equal(MarshalQNumber(0.25, Q31), []byte{0x00, 0x00, 0x00, 0x20})
+	assert.Equal(MarshalQNumber(2, Q31), []byte{0xFF, 0xFF, 0xFF, 0x7F})
+	assert.Equal(MarshalQNumber(-1.5, QFormat{16, 16, true}),
+		[]byte{0x00, 0x80, 0xFE, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF})
+	assert.Equal(MarshalQNumber(-1, QFormat{4, 19, true}),
+		[]byte{0x00, 0x00, 0xF8, 0xFF})
+	assert.Panics(func() { MarshalQNumber(1, QFormat{}) })
+}
+
+func TestUnmarshalQNumber(t *testing.T) {
+	assert := assert.New(t)
+	var v Number
+	assert.Equal(UnmarshalQNumber(&v, Q7, []byte{0xC0, 0xFF}), 1)
+	assert.EqualValues(v, -0.5)
+	assert.Equal(UnmarshalQNumber(&v, Q15, []byte{0x00, 0x40}), 2)
+	assert.EqualValues(v, 0.5)
+	assert.Equal(UnmarshalQNumber(&v, Q31, []byte{0x00, 0x00, 0x00, 0xE0}), 4)
+	assert.EqualValues(v, -0.25)
+	assert.Equal(UnmarshalQNumber(&v, QFormat{16, 16, true},
+		[]byte{0x00, 0x80, 0xFE, 0xFF, 0x01, 0x00, 0x00, 0x00}), 8)
+	assert.EqualValues(v, -1.5)
+	assert.Equal(UnmarshalQNumber(&v, QFormat{16, 16, true},
+		[]byte{0x00, 0x80, 0xFE, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}), 8)
+	assert.EqualValues(v, -1.5)
+	assert.Panics(func() { UnmarshalQNumber(&v, Q15, []byte{0x00}) })
+	assert.Panics(func() { UnmarshalQNumber(&v, QFormat{}, []byte{0x00}) })
+}
@@ -0,0 +1,253 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/newkedison/core/algorithm"
+	"strconv"
+	"strings"
+)
+
+// Quantity is a Number with a unit of measure.
+type Quantity struct {
+	Value Number
+	Unit  *Unit
+}
+
+// NewQuantity creates a quantity, unit is parsed with ParseUnit.
+func NewQuantity(v Number, unit string) (Quantity, error) {
+	u, err := ParseUnit(unit)
+	if err != nil {
+		return Quantity{}, err
+	}
+	return Quantity{v, u}, nil
+}
+
+// ParseQuantity parses a number followed by an optional unit, e.g.
+// "12.5 kPa", "-3°C" or "1e3 m/s".
+func ParseQuantity(s string) (Quantity, error) {
+	s = strings.TrimSpace(s)
+	end := 0
+	for end < len(s) {
+		c := s[end]
+		if c >= '0' && c <= '9' || c == '.' || c == '+' || c == '-' ||
+			(c == 'e' || c == 'E') && end > 0 && end+1 < len(s) &&
+				strings.ContainsRune("+-0123456789", rune(s[end+1])) {
+			end++
+			continue
+		}
+		break
+	}
+	if end == 0 {
+		return Quantity{}, fmt.Errorf(
+			"core.ParseQuantity: no number in %q", s)
+	}
+	v, err := strconv.ParseFloat(s[:end], 64)
+	if err != nil {
+		return Quantity{}, fmt.Errorf(
+			"core.ParseQuantity: invalid number in %q", s)
+	}
+	u, err := ParseUnit(s[end:])
+	if err != nil {
+		return Quantity{}, err
+	}
+	return Quantity{Number(v), u}, nil
+}
+
+func (q Quantity) unit() *Unit {
+	if q.Unit == nil {
+		return Dimensionless
+	}
+	return q.Unit
+}
+
+// String returns the value followed by the unit symbol, e.g. "12.5 kPa".
+// Only "%" and units starting with "°" such as "°C" are not separated by a
+// space, other units like "1/s" need it to be read back.
+func (q Quantity) String() string {
+	s := strconv.FormatFloat(float64(q.Value), 'g', -1, 64)
+	sym := q.unit().Symbol
+	if sym == "" {
+		return s
+	}
+	if sym == "%" || strings.HasPrefix(sym, "°") {
+		return s + sym
+	}
+	return s + " " + sym
+}
+
+// SI returns the value in the coherent SI unit of the quantity's dimension.
+func (q Quantity) SI() Quantity {
+	u := q.unit()
+	return Quantity{u.ToSI(q.Value), coherentUnit(u.Dimension)}
+}
+
+// ConvertTo converts q to unit u, which must have the same dimension.
+func (q Quantity) ConvertTo(u *Unit) (Quantity, error) {
+	from := q.unit()
+	if !from.Compatible(u) {
+		return Quantity{}, fmt.Errorf(
+			"core.Quantity: can not convert %s to %s, dimension %s and %s",
+			from.Symbol, u.Symbol, from.Dimension, u.Dimension)
+	}
+	if from == u {
+		return q, nil
+	}
+	return Quantity{u.FromSI(from.ToSI(q.Value)), u}, nil
+}
+
+// Convert converts q to the unit parsed from unit.
+func (q Quantity) Convert(unit string) (Quantity, error) {
+	u, err := ParseUnit(unit)
+	if err != nil {
+		return Quantity{}, err
+	}
+	return q.ConvertTo(u)
+}
+
+// Add converts o to the unit of q and adds the values. Units with an offset
+// are converted as absolute values, so 10 °C + 50 °F is 20 °C.
+func (q Quantity) Add(o Quantity) (Quantity, error) {
+	c, err := o.ConvertTo(q.unit())
+	if err != nil {
+		return Quantity{}, err
+	}
+	return Quantity{q.Value + c.Value, q.unit()}, nil
+}
+
+// Sub converts o to the unit of q and subtracts the values.
+func (q Quantity) Sub(o Quantity) (Quantity, error) {
+	c, err := o.ConvertTo(q.unit())
+	if err != nil {
+		return Quantity{}, err
+	}
+	return Quantity{q.Value - c.Value, q.unit()}, nil
+}
+
+// Mul multiplies two quantities, the result is in coherent SI units, e.g.
+// 2 kW * 3 h is 21600000 J. Units with an offset are rejected.
+func (q Quantity) Mul(o Quantity) (Quantity, error) {
+	if err := checkNoOffset("Mul", q, o); err != nil {
+		return Quantity{}, err
+	}
+	a, b := q.SI(), o.SI()
+	return Quantity{a.Value * b.Value,
+		coherentUnit(a.Unit.Dimension.Mul(b.Unit.Dimension))}, nil
+}
+
+// Div divides two quantities, the result is in coherent SI units.
+func (q Quantity) Div(o Quantity) (Quantity, error) {
+	if err := checkNoOffset("Div", q, o); err != nil {
+		return Quantity{}, err
+	}
+	a, b := q.SI(), o.SI()
+	return Quantity{a.Value / b.Value,
+		coherentUnit(a.Unit.Dimension.Div(b.Unit.Dimension))}, nil
+}
+
+// Scale multiplies the value by k keeping the unit.
+func (q Quantity) Scale(k Number) Quantity {
+	return Quantity{q.Value * k, q.Unit}
+}
+
+// Cmp compares q and o after converting them to SI, it returns -1, 0 or 1.
+func (q Quantity) Cmp(o Quantity) (int, error) {
+	if !q.unit().Compatible(o.unit()) {
+		return 0, fmt.Errorf("core.Quantity.Cmp: dimension %s and %s",
+			q.unit().Dimension, o.unit().Dimension)
+	}
+	a, b := q.SI().Value, o.SI().Value
+	switch {
+	case a < b:
+		return -1, nil
+	case a > b:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+func checkNoOffset(op string, qs ...Quantity) error {
+	for _, q := range qs {
+		if q.unit().Offset != 0 {
+			return fmt.Errorf("core.Quantity.%s: can not use %s, convert to K",
+				op, q.unit().Symbol)
+		}
+	}
+	return nil
+}
+
+var quantitySerializeVersion int32 = 0
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (q Quantity) MarshalBinary() ([]byte, error) {
+	buf := new(bytes.Buffer)
+	buf.Write(MarshalSimpleType(quantitySerializeVersion))
+	buf.Write(MarshalSimpleType(float64(q.Value)))
+	buf.Write(MarshalString(q.unit().Symbol))
+	return algorithm.AppendCrc16(buf.Bytes()), nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (q *Quantity) UnmarshalBinary(data []byte) (err error) {
+	defer SetErrorWhenNotEnoughDataErrorPanic(
+		"core.Quantity.UnmarshalBinary", &err)()
+	CheckBufferSize(data, 4)
+	var ver int32
+	offset := UnmashalSimpleType(&ver, data)
+	switch ver {
+	case 0:
+		var v float64
+		var sym string
+		offset += UnmashalSimpleType(&v, data[offset:])
+		offset += UnmarshalString(&sym, data[offset:])
+		CheckBufferSize(data, 2, offset)
+		if !algorithm.VerifyCrc16(data[:offset+2]) {
+			return errors.New("core.Quantity.UnmarshalBinary: CRC check fail")
+		}
+		u, err := ParseUnit(sym)
+		if err != nil {
+			return err
+		}
+		*q = Quantity{Number(v), u}
+	default:
+		return errors.New("core.Quantity.UnmarshalBinary: version error")
+	}
+	return nil
+}
+
+type quantityJSON struct {
+	Value float64 `json:"value"`
+	Unit  string  `json:"unit"`
+}
+
+// MarshalJSON implements the json.Marshaler interface, the result looks like
+// {"value":12.5,"unit":"kPa"}.
+func (q Quantity) MarshalJSON() ([]byte, error) {
+	return json.Marshal(quantityJSON{float64(q.Value), q.unit().Symbol})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface, it accepts the
+// object written by MarshalJSON or a string parsed by ParseQuantity.
+func (q *Quantity) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		v, err := ParseQuantity(s)
+		if err != nil {
+			return err
+		}
+		*q = v
+		return nil
+	}
+	var tmp quantityJSON
+	if err := json.Unmarshal(data, &tmp); err != nil {
+		return err
+	}
+	u, err := ParseUnit(tmp.Unit)
+	if err != nil {
+		return err
+	}
+	*q = Quantity{Number(tmp.Value), u}
+	return nil
+}
@@ -0,0 +1,210 @@
+package core
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func mustQuantity(s string) Quantity {
+	q, err := ParseQuantity(s)
+	if err != nil {
+		panic(err)
+	}
+	return q
+}
+
+func TestParseQuantity(t *testing.T) {
+	assert := assert.New(t)
+	check := func(s string, v Number, unit string) {
+		q, err := ParseQuantity(s)
+		if assert.NoError(err, s) {
+			assert.Equal(v, q.Value, s)
+			assert.Equal(unit, q.Unit.Symbol, s)
+		}
+	}
+	check("12.5 kPa", 12.5, "kPa")
+	check("12.5kPa", 12.5, "kPa")
+	check("  -3 °C ", -3, "°C")
+	check("-3°C", -3, "°C")
+	check("1e3 m/s", 1000, "m/s")
+	check("1.5E-3 A", 0.0015, "A")
+	check("+7 Em", 7, "Em")
+	check("42", 42, "")
+	check("50 %", 50, "%")
+	_, err := ParseQuantity("kPa")
+	assert.EqualError(err, `core.ParseQuantity: no number in "kPa"`)
+	_, err = ParseQuantity("1.2.3 m")
+	assert.EqualError(err, `core.ParseQuantity: invalid number in "1.2.3 m"`)
+	_, err = ParseQuantity("12 xyz")
+	assert.Error(err)
+}
+
+func TestQuantityString(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal(mustQuantity("12.5 kPa").String(), "12.5 kPa")
+	assert.Equal(mustQuantity("-3°C").String(), "-3°C")
+	assert.Equal(mustQuantity("50%").String(), "50%")
+	assert.Equal(mustQuantity("42").String(), "42")
+	assert.Equal(mustQuantity("1 kΩ").String(), "1 kΩ")
+	assert.Equal(Quantity{Value: 1}.String(), "1")
+	// the string parses back to the same quantity
+	for _, in := range []string{"3 1/s", "12.5 kPa", "-3°C", "50%", "42",
+		"2 m/s", "1 kg·m^2·s^-2", "7 °F"} {
+		q := mustQuantity(in)
+		parsed, err := ParseQuantity(q.String())
+		if assert.NoError(err, in) {
+			assert.Equal(parsed.Value, q.Value, in)
+			assert.Equal(parsed.Unit.Symbol, q.Unit.Symbol, in)
+		}
+	}
+	assert.Equal(mustQuantity("3 1/s").String(), "3 1/s")
+}
+
+func TestQuantityConvert(t *testing.T) {
+	assert := assert.New(t)
+	check := func(from, to string, expect Number) {
+		q, err := mustQuantity(from).Convert(to)
+		if assert.NoError(err, from) {
+			assert.InDelta(float64(expect), float64(q.Value), 1e-9, from)
+			assert.Equal(to, q.Unit.Symbol)
+		}
+	}
+	check("1500 mV", "V", 1.5)
+	check("1 kWh", "J", 3.6e6)
+	check("3600000 J", "kWh", 1)
+	check("1 bar", "kPa", 100)
+	check("1 atm", "Pa", 101325)
+	check("100 km/h", "m/s", 100/3.6)
+	check("100 °C", "°F", 212)
+	check("-40 °F", "°C", -40)
+	check("0 °C", "K", 273.15)
+	check("20 °C", "°F", 68)
+	check("1 psi", "kPa", 6.894757293168361)
+	check("90 min", "h", 1.5)
+	check("1 L", "cm³", 1000)
+	check("12 %", "", 0.12)
+	check("3000 rpm", "Hz", 50)
+	q, err := mustQuantity("20 degC").Convert("degF")
+	assert.NoError(err)
+	assert.Equal(q.Unit.Symbol, "°F")
+	_, err = mustQuantity("1 m").Convert("s")
+	assert.EqualError(err,
+		"core.Quantity: can not convert m to s, dimension m and s")
+	_, err = mustQuantity("1 m").Convert("zz")
+	assert.Error(err)
+	q = mustQuantity("1 m")
+	c, err := q.ConvertTo(q.Unit)
+	assert.NoError(err)
+	assert.Equal(q, c)
+}
+
+func TestQuantityArithmetic(t *testing.T) {
+	assert := assert.New(t)
+	q, err := mustQuantity("1 V").Add(mustQuantity("500 mV"))
+	assert.NoError(err)
+	assert.Equal(q.String(), "1.5 V")
+	q, err = mustQuantity("500 mV").Add(mustQuantity("1 V"))
+	assert.NoError(err)
+	assert.Equal(q.String(), "1500 mV")
+	q, err = mustQuantity("1 km").Sub(mustQuantity("1 m"))
+	assert.NoError(err)
+	assert.Equal(q.String(), "0.999 km")
+	q, err = mustQuantity("10 °C").Add(mustQuantity("50 °F"))
+	assert.NoError(err)
+	assert.InDelta(float64(q.Value), 20, 1e-9)
+	_, err = mustQuantity("1 V").Add(mustQuantity("1 A"))
+	assert.Error(err)
+	_, err = mustQuantity("1 V").Sub(mustQuantity("1 A"))
+	assert.Error(err)
+
+	q, err = mustQuantity("2 kW").Mul(mustQuantity("3 h"))
+	assert.NoError(err)
+	assert.Equal(q.String(), "2.16e+07 J")
+	q, err = q.Convert("kWh")
+	assert.NoError(err)
+	assert.InDelta(float64(q.Value), 6, 1e-9)
+	q, err = mustQuantity("12 V").Mul(mustQuantity("2 A"))
+	assert.NoError(err)
+	assert.Equal(q.String(), "24 W")
+	q, err = mustQuantity("100 km").Div(mustQuantity("2 h"))
+	assert.NoError(err)
+	assert.Equal(q.Unit.Symbol, "m·s^-1")
+	q, err = q.Convert("km/h")
+	assert.NoError(err)
+	assert.InDelta(float64(q.Value), 50, 1e-9)
+	q, err = mustQuantity("6 m").Div(mustQuantity("3 m"))
+	assert.NoError(err)
+	assert.Equal(q.String(), "2")
+	q, err = mustQuantity("1 m").Mul(mustQuantity("1 kg"))
+	assert.NoError(err)
+	assert.Equal(q.Unit.Symbol, "kg·m")
+	_, err = mustQuantity("10 °C").Mul(mustQuantity("2"))
+	assert.EqualError(err, "core.Quantity.Mul: can not use °C, convert to K")
+	_, err = mustQuantity("2").Div(mustQuantity("10 °F"))
+	assert.Error(err)
+	assert.Equal(mustQuantity("2 kPa").Scale(3).String(), "6 kPa")
+}
+
+func TestQuantityCmp(t *testing.T) {
+	assert := assert.New(t)
+	c, err := mustQuantity("1 km").Cmp(mustQuantity("999 m"))
+	assert.NoError(err)
+	assert.Equal(c, 1)
+	c, err = mustQuantity("0 °C").Cmp(mustQuantity("33 °F"))
+	assert.NoError(err)
+	assert.Equal(c, -1)
+	c, err = mustQuantity("1 km").Cmp(mustQuantity("1000 m"))
+	assert.NoError(err)
+	assert.Equal(c, 0)
+	c, err = mustQuantity("1 mV").Cmp(mustQuantity("1 V"))
+	assert.NoError(err)
+	assert.Equal(c, -1)
+	_, err = mustQuantity("1 m").Cmp(mustQuantity("1 s"))
+	assert.Error(err)
+}
+
+func TestQuantityMarshalBinary(t *testing.T) {
+	assert := assert.New(t)
+	q := mustQuantity("12.5 kPa")
+	data, err := q.MarshalBinary()
+	assert.NoError(err)
+	assert.Len(data, 4+8+2+3+2)
+	var q2 Quantity
+	assert.NoError(q2.UnmarshalBinary(data))
+	assert.Equal(q2.String(), "12.5 kPa")
+	assert.Error(q2.UnmarshalBinary(data[:len(data)-1]))
+	data[5]++
+	assert.EqualError(q2.UnmarshalBinary(data),
+		"core.Quantity.UnmarshalBinary: CRC check fail")
+	assert.EqualError(q2.UnmarshalBinary([]byte{0x01, 0x00, 0x00, 0x00}),
+		"core.Quantity.UnmarshalBinary: version error")
+
+	q, _ = NewQuantity(3, "km/h")
+	data, err = MarshalObject(q)
+	assert.NoError(err)
+	UnmarshalObject(&q2, data)
+	assert.Equal(q2.String(), "3 km/h")
+	assert.InEpsilon(q2.Unit.Factor, 1/3.6, 1e-12)
+}
+
+func TestQuantityJSON(t *testing.T) {
+	assert := assert.New(t)
+	data, err := json.Marshal(mustQuantity("12.5 kPa"))
+	assert.NoError(err)
+	assert.Equal(string(data), `{"value":12.5,"unit":"kPa"}`)
+	data, err = json.Marshal(Quantity{Value: 2})
+	assert.NoError(err)
+	assert.Equal(string(data), `{"value":2,"unit":""}`)
+	var q Quantity
+	assert.NoError(json.Unmarshal([]byte(`{"value":-3,"unit":"°C"}`), &q))
+	assert.Equal(q.String(), "-3°C")
+	assert.NoError(json.Unmarshal([]byte(`"1.5 kWh"`), &q))
+	assert.Equal(q.String(), "1.5 kWh")
+	assert.Error(json.Unmarshal([]byte(`{"value":1,"unit":"zz"}`), &q))
+	assert.Error(json.Unmarshal([]byte(`"zz"`), &q))
+	assert.Error(json.Unmarshal([]byte(`[1]`), &q))
+	assert.Equal(q.String(), "1.5 kWh")
+	_, err = NewQuantity(1, "zz")
+	assert.Error(err)
+}
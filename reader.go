@@ -0,0 +1,297 @@
+package core
+
+import (
+	"encoding"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+)
+
+// ByteArrayReader reads typed values from a ByteArray. The first failure is
+// recorded and returned by Err, after that all reads are no-ops returning
+// zero values, so a sequence of reads needs only one error check at the end.
+type ByteArrayReader struct {
+	data ByteArray
+	pos  int
+	err  error
+}
+
+func NewByteArrayReader(ba ByteArray) *ByteArrayReader {
+	return &ByteArrayReader{data: ba}
+}
+
+// Reset starts reading ba from the beginning and clears the error.
+func (r *ByteArrayReader) Reset(ba ByteArray) {
+	*r = ByteArrayReader{data: ba}
+}
+
+func (r *ByteArrayReader) Err() error {
+	return r.err
+}
+
+func (r *ByteArrayReader) Pos() int {
+	return r.pos
+}
+
+func (r *ByteArrayReader) Len() int {
+	return len(r.data)
+}
+
+// Remaining returns the number of unread bytes.
+func (r *ByteArrayReader) Remaining() int {
+	if r.pos >= len(r.data) {
+		return 0
+	}
+	return len(r.data) - r.pos
+}
+
+func (r *ByteArrayReader) fail(err error) {
+	if r.err == nil {
+		r.err = err
+	}
+}
+
+// next returns the following n bytes and advances, ok is false when failed.
+func (r *ByteArrayReader) next(n int) (b []byte, ok bool) {
+	if b, ok = r.peek(n); ok {
+		r.pos += n
+	}
+	return
+}
+
+func (r *ByteArrayReader) peek(n int) ([]byte, bool) {
+	if r.err != nil {
+		return nil, false
+	}
+	if n < 0 || r.pos+n > len(r.data) {
+		r.fail(errors.New("core.ByteArrayReader: " +
+			string(NewNotEnoughDataError(n, len(r.data), r.pos))))
+		return nil, false
+	}
+	return r.data[r.pos : r.pos+n], true
+}
+
+// Seek implements the io.Seeker interface. Seeking beyond the end is
+// allowed, the following reads fail.
+func (r *ByteArrayReader) Seek(offset int64, whence int) (int64, error) {
+	if r.err != nil {
+		return int64(r.pos), r.err
+	}
+	var pos int64
+	switch whence {
+	case io.SeekStart:
+		pos = offset
+	case io.SeekCurrent:
+		pos = int64(r.pos) + offset
+	case io.SeekEnd:
+		pos = int64(len(r.data)) + offset
+	default:
+		r.fail(errors.New("core.ByteArrayReader.Seek: invalid whence"))
+		return int64(r.pos), r.err
+	}
+	if pos < 0 {
+		r.fail(errors.New("core.ByteArrayReader.Seek: negative position"))
+		return int64(r.pos), r.err
+	}
+	r.pos = int(pos)
+	return pos, nil
+}
+
+func (r *ByteArrayReader) Skip(n int) {
+	r.next(n)
+}
+
+// Read implements the io.Reader interface, io.EOF is returned at the end of
+// the data and is not recorded as an error.
+func (r *ByteArrayReader) Read(p []byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	if r.Remaining() == 0 {
+		if len(p) == 0 {
+			return 0, nil
+		}
+		return 0, io.EOF
+	}
+	n := copy(p, r.data[r.pos:])
+	r.pos += n
+	return n, nil
+}
+
+// ReadByte implements the io.ByteReader interface.
+func (r *ByteArrayReader) ReadByte() (byte, error) {
+	b, ok := r.next(1)
+	if !ok {
+		return 0, r.err
+	}
+	return b[0], nil
+}
+
+// ReadBytes returns a copy of the next n bytes.
+func (r *ByteArrayReader) ReadBytes(n int) []byte {
+	b, ok := r.next(n)
+	if !ok {
+		return nil
+	}
+	return append([]byte{}, b...)
+}
+
+// Peek returns the next n bytes without advancing, the result shares the
+// underlying data. An error is returned but not recorded.
+func (r *ByteArrayReader) Peek(n int) ([]byte, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	b, ok := r.peek(n)
+	if !ok {
+		err := r.err
+		r.err = nil
+		return nil, err
+	}
+	return b, nil
+}
+
+func (r *ByteArrayReader) PeekByte() (byte, error) {
+	b, err := r.Peek(1)
+	if err != nil {
+		return 0, err
+	}
+	return b[0], nil
+}
+
+func (r *ByteArrayReader) ReadUint8() uint8 {
+	if b, ok := r.next(1); ok {
+		return b[0]
+	}
+	return 0
+}
+
+func (r *ByteArrayReader) ReadInt8() int8 {
+	return int8(r.ReadUint8())
+}
+
+func (r *ByteArrayReader) ReadUint16(order binary.ByteOrder) uint16 {
+	if b, ok := r.next(2); ok {
+		return order.Uint16(b)
+	}
+	return 0
+}
+
+func (r *ByteArrayReader) ReadUint32(order binary.ByteOrder) uint32 {
+	if b, ok := r.next(4); ok {
+		return order.Uint32(b)
+	}
+	return 0
+}
+
+func (r *ByteArrayReader) ReadUint64(order binary.ByteOrder) uint64 {
+	if b, ok := r.next(8); ok {
+		return order.Uint64(b)
+	}
+	return 0
+}
+
+func (r *ByteArrayReader) ReadInt16(order binary.ByteOrder) int16 {
+	return int16(r.ReadUint16(order))
+}
+
+func (r *ByteArrayReader) ReadInt32(order binary.ByteOrder) int32 {
+	return int32(r.ReadUint32(order))
+}
+
+func (r *ByteArrayReader) ReadInt64(order binary.ByteOrder) int64 {
+	return int64(r.ReadUint64(order))
+}
+
+func (r *ByteArrayReader) ReadFloat32(order binary.ByteOrder) float32 {
+	return math.Float32frombits(r.ReadUint32(order))
+}
+
+func (r *ByteArrayReader) ReadFloat64(order binary.ByteOrder) float64 {
+	return math.Float64frombits(r.ReadUint64(order))
+}
+
+func (r *ByteArrayReader) ReadUint16BE() uint16 {
+	return r.ReadUint16(binary.BigEndian)
+}
+
+func (r *ByteArrayReader) ReadUint16LE() uint16 {
+	return r.ReadUint16(binary.LittleEndian)
+}
+
+func (r *ByteArrayReader) ReadInt16BE() int16 {
+	return r.ReadInt16(binary.BigEndian)
+}
+
+func (r *ByteArrayReader) ReadInt16LE() int16 {
+	return r.ReadInt16(binary.LittleEndian)
+}
+
+func (r *ByteArrayReader) ReadUint32BE() uint32 {
+	return r.ReadUint32(binary.BigEndian)
+}
+
+func (r *ByteArrayReader) ReadUint32LE() uint32 {
+	return r.ReadUint32(binary.LittleEndian)
+}
+
+func (r *ByteArrayReader) ReadInt32BE() int32 {
+	return r.ReadInt32(binary.BigEndian)
+}
+
+func (r *ByteArrayReader) ReadInt32LE() int32 {
+	return r.ReadInt32(binary.LittleEndian)
+}
+
+func (r *ByteArrayReader) ReadUint64BE() uint64 {
+	return r.ReadUint64(binary.BigEndian)
+}
+
+func (r *ByteArrayReader) ReadUint64LE() uint64 {
+	return r.ReadUint64(binary.LittleEndian)
+}
+
+func (r *ByteArrayReader) ReadInt64BE() int64 {
+	return r.ReadInt64(binary.BigEndian)
+}
+
+func (r *ByteArrayReader) ReadInt64LE() int64 {
+	return r.ReadInt64(binary.LittleEndian)
+}
+
+func (r *ByteArrayReader) ReadFloat32BE() float32 {
+	return r.ReadFloat32(binary.BigEndian)
+}
+
+func (r *ByteArrayReader) ReadFloat32LE() float32 {
+	return r.ReadFloat32(binary.LittleEndian)
+}
+
+func (r *ByteArrayReader) ReadFloat64BE() float64 {
+	return r.ReadFloat64(binary.BigEndian)
+}
+
+func (r *ByteArrayReader) ReadFloat64LE() float64 {
+	return r.ReadFloat64(binary.LittleEndian)
+}
+
+// ReadString reads a string written by MarshalString.
+func (r *ByteArrayReader) ReadString() string {
+	n := r.ReadUint16(defaultByteOrder)
+	b, _ := r.next(int(n))
+	return string(b)
+}
+
+// ReadObject reads an object written by MarshalObject, an error returned by
+// dest.UnmarshalBinary is recorded.
+func (r *ByteArrayReader) ReadObject(dest encoding.BinaryUnmarshaler) {
+	n := r.ReadUint32(defaultByteOrder)
+	b, ok := r.next(int(n))
+	if !ok {
+		return
+	}
+	if err := dest.UnmarshalBinary(b); err != nil {
+		r.fail(err)
+	}
+}
@@ -0,0 +1,183 @@
+package core
+
+import (
+	"encoding/binary"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"io/ioutil"
+	"testing"
+)
+
+func TestByteArrayReaderTyped(t *testing.T) {
+	assert := assert.New(t)
+	var ba ByteArray
+	ba.AppendBytes([]byte{0xFE, 0x81})
+	ba.AppendBytes([]byte{0x12, 0x34, 0x12, 0x34, 0xFF, 0xFE, 0xFE, 0xFF})
+	ba.AppendBytes([]byte{0x12, 0x34, 0x56, 0x78, 0x78, 0x56, 0x34, 0x12})
+	ba.AppendBytes([]byte{0xFF, 0xFF, 0xFF, 0xFE, 0xFE, 0xFF, 0xFF, 0xFF})
+	ba.AppendBytes([]byte{1, 2, 3, 4, 5, 6, 7, 8, 8, 7, 6, 5, 4, 3, 2, 1})
+	ba.AppendBytes([]byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFE})
+	ba.AppendBytes([]byte{0xFE, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF})
+	ba.AppendBytes([]byte{0x3F, 0xC0, 0x00, 0x00, 0x00, 0x00, 0xC0, 0x3F})
+	ba.AppendBytes([]byte{0x3F, 0xF8, 0, 0, 0, 0, 0, 0})
+	ba.AppendBytes([]byte{0, 0, 0, 0, 0, 0, 0xF8, 0x3F})
+	r := NewByteArrayReader(ba)
+	assert.Equal(r.Len(), len(ba))
+	assert.Equal(r.ReadUint8(), uint8(0xFE))
+	assert.Equal(r.ReadInt8(), int8(-127))
+	assert.Equal(r.ReadUint16BE(), uint16(0x1234))
+	assert.Equal(r.ReadUint16LE(), uint16(0x3412))
+	assert.Equal(r.ReadInt16BE(), int16(-2))
+	assert.Equal(r.ReadInt16LE(), int16(-2))
+	assert.Equal(r.ReadUint32BE(), uint32(0x12345678))
+	assert.Equal(r.ReadUint32LE(), uint32(0x12345678))
+	assert.Equal(r.ReadInt32BE(), int32(-2))
+	assert.Equal(r.ReadInt32LE(), int32(-2))
+	assert.Equal(r.ReadUint64BE(), uint64(0x0102030405060708))
+	assert.Equal(r.ReadUint64LE(), uint64(0x0102030405060708))
+	assert.Equal(r.ReadInt64BE(), int64(-2))
+	assert.Equal(r.ReadInt64LE(), int64(-2))
+	assert.Equal(r.ReadFloat32BE(), float32(1.5))
+	assert.Equal(r.ReadFloat32LE(), float32(1.5))
+	assert.Equal(r.ReadFloat64BE(), 1.5)
+	assert.Equal(r.ReadFloat64LE(), 1.5)
+	assert.Equal(r.Remaining(), 0)
+	assert.NoError(r.Err())
+}
+
+func TestByteArrayReaderStickyError(t *testing.T) {
+	assert := assert.New(t)
+	r := NewByteArrayReader(ByteArray{1, 2, 3})
+	assert.Equal(r.ReadUint16(binary.LittleEndian), uint16(0x0201))
+	assert.Equal(r.ReadUint32BE(), uint32(0))
+	assert.EqualError(r.Err(),
+		"core.ByteArrayReader: Not enought data, require 4, offer 3-2=1")
+	// later reads are no-ops even when there is enough data
+	assert.Equal(r.ReadUint8(), uint8(0))
+	assert.Equal(r.Pos(), 2)
+	_, err := r.ReadByte()
+	assert.Equal(err, r.Err())
+	n, err := r.Read(make([]byte, 1))
+	assert.Equal(n, 0)
+	assert.Equal(err, r.Err())
+	_, err = r.Seek(0, io.SeekStart)
+	assert.Equal(err, r.Err())
+	r.Reset(ByteArray{5})
+	assert.NoError(r.Err())
+	assert.Equal(r.ReadUint8(), uint8(5))
+	r.Skip(-1)
+	assert.Error(r.Err())
+}
+
+func TestByteArrayReaderSeek(t *testing.T) {
+	assert := assert.New(t)
+	r := NewByteArrayReader(ByteArray{0, 1, 2, 3, 4, 5})
+	var _ io.ReadSeeker = r
+	var _ io.ByteReader = r
+	pos, err := r.Seek(2, io.SeekStart)
+	assert.NoError(err)
+	assert.EqualValues(pos, 2)
+	assert.Equal(r.ReadUint8(), uint8(2))
+	pos, err = r.Seek(1, io.SeekCurrent)
+	assert.NoError(err)
+	assert.EqualValues(pos, 4)
+	assert.Equal(r.Remaining(), 2)
+	pos, err = r.Seek(-1, io.SeekEnd)
+	assert.NoError(err)
+	assert.EqualValues(pos, 5)
+	r.Skip(1)
+	assert.Equal(r.Remaining(), 0)
+	pos, err = r.Seek(10, io.SeekStart)
+	assert.NoError(err)
+	assert.EqualValues(pos, 10)
+	assert.Equal(r.Remaining(), 0)
+	r.Skip(0)
+	assert.Error(r.Err())
+
+	r.Reset(ByteArray{0, 1})
+	_, err = r.Seek(-1, io.SeekStart)
+	assert.EqualError(err, "core.ByteArrayReader.Seek: negative position")
+	r.Reset(ByteArray{0, 1})
+	_, err = r.Seek(0, 3)
+	assert.EqualError(err, "core.ByteArrayReader.Seek: invalid whence")
+}
+
+func TestByteArrayReaderRead(t *testing.T) {
+	assert := assert.New(t)
+	r := NewByteArrayReader(ByteArray{0, 1, 2, 3, 4})
+	b, err := r.ReadByte()
+	assert.NoError(err)
+	assert.Equal(b, byte(0))
+	p := make([]byte, 3)
+	n, err := r.Read(p)
+	assert.NoError(err)
+	assert.Equal(n, 3)
+	assert.Equal(p, []byte{1, 2, 3})
+	n, err = r.Read(p)
+	assert.NoError(err)
+	assert.Equal(n, 1)
+	n, err = r.Read(p)
+	assert.Equal(err, io.EOF)
+	assert.Equal(n, 0)
+	assert.NoError(r.Err())
+	r.Reset(ByteArray{0, 1, 2, 3, 4})
+	all, err := ioutil.ReadAll(r)
+	assert.NoError(err)
+	assert.Equal(all, []byte{0, 1, 2, 3, 4})
+	r.Reset(ByteArray{0, 1, 2})
+	assert.Equal(r.ReadBytes(2), []byte{0, 1})
+	assert.Equal(r.ReadBytes(0), []byte{})
+	assert.Nil(r.ReadBytes(2))
+	assert.Error(r.Err())
+}
+
+func TestByteArrayReaderPeek(t *testing.T) {
+	assert := assert.New(t)
+	r := NewByteArrayReader(ByteArray{7, 8})
+	b, err := r.PeekByte()
+	assert.NoError(err)
+	assert.Equal(b, byte(7))
+	p, err := r.Peek(2)
+	assert.NoError(err)
+	assert.Equal(p, []byte{7, 8})
+	_, err = r.Peek(3)
+	assert.Error(err)
+	// a failed peek is not recorded
+	assert.NoError(r.Err())
+	assert.Equal(r.Pos(), 0)
+	r.Skip(2)
+	_, err = r.PeekByte()
+	assert.Error(err)
+	assert.NoError(r.Err())
+}
+
+type failUnmarshaler struct{}
+
+func (failUnmarshaler) UnmarshalBinary([]byte) error {
+	return errors.New("fail")
+}
+
+func TestByteArrayReaderObject(t *testing.T) {
+	assert := assert.New(t)
+	var ba ByteArray
+	ba.AppendBytes(MarshalString("hello"))
+	ba.AppendBytes(MarshalString(""))
+	obj, _ := MarshalObject(Number(1.5))
+	ba.AppendBytes(obj)
+	ba.AppendBytes(obj)
+	r := NewByteArrayReader(ba)
+	assert.Equal(r.ReadString(), "hello")
+	assert.Equal(r.ReadString(), "")
+	var n Number
+	r.ReadObject(&n)
+	assert.NoError(r.Err())
+	assert.EqualValues(n, 1.5)
+	r.ReadObject(failUnmarshaler{})
+	assert.EqualError(r.Err(), "fail")
+	assert.Equal(r.Remaining(), 0)
+
+	r.Reset(ba[:4])
+	assert.Equal(r.ReadString(), "")
+	assert.Error(r.Err())
+}
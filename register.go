@@ -0,0 +1,191 @@
+package core
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/bits"
+)
+
+// RegisterOrder describes how a value wider than one 16-bit register is
+// spread across registers. The letters name the bytes of the big-endian
+// value, so for a 32-bit value 0xAABBCCDD in ABCD order the registers are
+// 0xAABB 0xCCDD. 64-bit values extend the same pattern: CDAB reverses the
+// order of all words and BADC swaps the bytes inside each word.
+type RegisterOrder int
+
+const (
+	RegisterOrderABCD RegisterOrder = iota
+	RegisterOrderCDAB
+	RegisterOrderBADC
+	RegisterOrderDCBA
+)
+
+func (o RegisterOrder) String() string {
+	switch o {
+	case RegisterOrderABCD:
+		return "ABCD"
+	case RegisterOrderCDAB:
+		return "CDAB"
+	case RegisterOrderBADC:
+		return "BADC"
+	case RegisterOrderDCBA:
+		return "DCBA"
+	}
+	return fmt.Sprintf("RegisterOrder(%d)", int(o))
+}
+
+func (o RegisterOrder) swapWords() bool {
+	return o == RegisterOrderCDAB || o == RegisterOrderDCBA
+}
+
+func (o RegisterOrder) swapBytes() bool {
+	return o == RegisterOrderBADC || o == RegisterOrderDCBA
+}
+
+type RegisterType int
+
+const (
+	RegisterInt16 RegisterType = iota
+	RegisterUint16
+	RegisterInt32
+	RegisterUint32
+	RegisterFloat32
+	RegisterInt64
+	RegisterUint64
+	RegisterFloat64
+)
+
+// RegisterCodec converts a Number to and from a block of 16-bit registers as
+// exposed by Modbus devices.
+type RegisterCodec struct {
+	Type  RegisterType
+	Order RegisterOrder
+}
+
+// Count returns the number of registers used by one value.
+func (c RegisterCodec) Count() int {
+	switch c.Type {
+	case RegisterInt16, RegisterUint16:
+		return 1
+	case RegisterInt32, RegisterUint32, RegisterFloat32:
+		return 2
+	case RegisterInt64, RegisterUint64, RegisterFloat64:
+		return 4
+	}
+	panic(fmt.Sprintf("core.RegisterCodec: unknown type %d", int(c.Type)))
+}
+
+// Encode converts v to registers. Integer types truncate like
+// Number.ToInt32 and friends.
+func (c RegisterCodec) Encode(v Number) []uint16 {
+	var raw uint64
+	switch c.Type {
+	case RegisterInt16:
+		raw = uint64(uint16(v.ToInt16()))
+	case RegisterUint16:
+		raw = uint64(v.ToUint16())
+	case RegisterInt32:
+		raw = uint64(uint32(v.ToInt32()))
+	case RegisterUint32:
+		raw = uint64(v.ToUint32())
+	case RegisterFloat32:
+		raw = uint64(math.Float32bits(v.ToFloat32()))
+	case RegisterInt64:
+		raw = uint64(v.ToInt64())
+	case RegisterUint64:
+		raw = v.ToUint64()
+	case RegisterFloat64:
+		raw = math.Float64bits(v.ToFloat64())
+	}
+	n := c.Count()
+	regs := make([]uint16, n)
+	for i := range regs {
+		regs[i] = uint16(raw >> uint(16*(n-1-i)))
+	}
+	return c.arrange(regs)
+}
+
+// Decode converts the first Count registers of regs to a Number.
+func (c RegisterCodec) Decode(regs []uint16) (Number, error) {
+	n := c.Count()
+	if len(regs) < n {
+		return 0, fmt.Errorf(
+			"core.RegisterCodec.Decode: require %d registers, offer %d",
+			n, len(regs))
+	}
+	regs = c.arrange(append([]uint16(nil), regs[:n]...))
+	var raw uint64
+	for _, r := range regs {
+		raw = raw<<16 | uint64(r)
+	}
+	switch c.Type {
+	case RegisterInt16:
+		return Number(int16(raw)), nil
+	case RegisterUint16:
+		return Number(uint16(raw)), nil
+	case RegisterInt32:
+		return Number(int32(raw)), nil
+	case RegisterUint32:
+		return Number(uint32(raw)), nil
+	case RegisterFloat32:
+		return Number(math.Float32frombits(uint32(raw))), nil
+	case RegisterInt64:
+		return Number(int64(raw)), nil
+	case RegisterUint64:
+		return Number(raw), nil
+	}
+	return Number(math.Float64frombits(raw)), nil
+}
+
+// arrange converts between ABCD order and c.Order in place, the operation is
+// its own inverse.
+func (c RegisterCodec) arrange(regs []uint16) []uint16 {
+	if c.Order.swapBytes() {
+		for i := range regs {
+			regs[i] = bits.ReverseBytes16(regs[i])
+		}
+	}
+	if c.Order.swapWords() {
+		for i, j := 0, len(regs)-1; i < j; i, j = i+1, j-1 {
+			regs[i], regs[j] = regs[j], regs[i]
+		}
+	}
+	return regs
+}
+
+// AppendRegisters appends regs as big-endian 16-bit words, the register
+// layout used on the Modbus wire.
+func (ba *ByteArray) AppendRegisters(regs []uint16) {
+	tmp := make([]byte, 2)
+	for _, r := range regs {
+		binary.BigEndian.PutUint16(tmp, r)
+		*ba = append(*ba, tmp...)
+	}
+}
+
+// Registers reads count big-endian registers starting at byte offset.
+func (ba ByteArray) Registers(offset, count int) ([]uint16, error) {
+	if offset < 0 || count < 0 || len(ba) < offset+2*count {
+		return nil, fmt.Errorf("core.ByteArray.Registers: %s",
+			string(NewNotEnoughDataError(2*count, len(ba), offset)))
+	}
+	regs := make([]uint16, count)
+	for i := range regs {
+		regs[i] = binary.BigEndian.Uint16(ba[offset+2*i:])
+	}
+	return regs, nil
+}
+
+// PutRegisters overwrites the bytes starting at offset with regs as
+// big-endian registers.
+func (ba ByteArray) PutRegisters(offset int, regs []uint16) error {
+	if offset < 0 || len(ba) < offset+2*len(regs) {
+		return fmt.Errorf("core.ByteArray.PutRegisters: %s",
+			string(NewNotEnoughDataError(2*len(regs), len(ba), offset)))
+	}
+	for i, r := range regs {
+		binary.BigEndian.PutUint16(ba[offset+2*i:], r)
+	}
+	return nil
+}
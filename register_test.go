@@ -0,0 +1,135 @@
+package core
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+func TestRegisterOrderString(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal(RegisterOrderABCD.String(), "ABCD")
+	assert.Equal(RegisterOrderCDAB.String(), "CDAB")
+	assert.Equal(RegisterOrderBADC.String(), "BADC")
+	assert.Equal(RegisterOrderDCBA.String(), "DCBA")
+	assert.Equal(RegisterOrder(9).String(), "RegisterOrder(9)")
+}
+
+func TestRegisterCodecCount(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal(RegisterCodec{RegisterInt16, RegisterOrderABCD}.Count(), 1)
+	assert.Equal(RegisterCodec{RegisterUint16, RegisterOrderABCD}.Count(), 1)
+	assert.Equal(RegisterCodec{RegisterInt32, RegisterOrderABCD}.Count(), 2)
+	assert.Equal(RegisterCodec{RegisterUint32, RegisterOrderABCD}.Count(), 2)
+	assert.Equal(RegisterCodec{RegisterFloat32, RegisterOrderABCD}.Count(), 2)
+	assert.Equal(RegisterCodec{RegisterInt64, RegisterOrderABCD}.Count(), 4)
+	assert.Equal(RegisterCodec{RegisterUint64, RegisterOrderABCD}.Count(), 4)
+	assert.Equal(RegisterCodec{RegisterFloat64, RegisterOrderABCD}.Count(), 4)
+	assert.Panics(func() { RegisterCodec{RegisterType(99), 0}.Count() })
+}
+
+func TestRegisterCodecEncode(t *testing.T) {
+	assert := assert.New(t)
+	// 123.456f = 0x42F6E979
+	f := Number(float32(123.456))
+	assert.Equal(RegisterCodec{RegisterFloat32, RegisterOrderABCD}.Encode(f),
+		[]uint16{0x42F6, 0xE979})
+	assert.Equal(RegisterCodec{RegisterFloat32, RegisterOrderCDAB}.Encode(f),
+		[]uint16{0xE979, 0x42F6})
+	assert.Equal(RegisterCodec{RegisterFloat32, RegisterOrderBADC}.Encode(f),
+		[]uint16{0xF642, 0x79E9})
+	assert.Equal(RegisterCodec{RegisterFloat32, RegisterOrderDCBA}.Encode(f),
+		[]uint16{0x79E9, 0xF642})
+	assert.Equal(RegisterCodec{RegisterInt16, RegisterOrderABCD}.Encode(-2),
+		[]uint16{0xFFFE})
+	assert.Equal(RegisterCodec{RegisterInt16, RegisterOrderCDAB}.Encode(0x1234),
+		[]uint16{0x1234})
+	assert.Equal(RegisterCodec{RegisterUint16, RegisterOrderBADC}.Encode(0x1234),
+		[]uint16{0x3412})
+	assert.Equal(RegisterCodec{RegisterInt32, RegisterOrderABCD}.Encode(-2),
+		[]uint16{0xFFFF, 0xFFFE})
+	assert.Equal(RegisterCodec{RegisterUint32, RegisterOrderCDAB}.Encode(0x12345678),
+		[]uint16{0x5678, 0x1234})
+	assert.Equal(RegisterCodec{RegisterInt64, RegisterOrderABCD}.Encode(0x0102030405),
+		[]uint16{0x0000, 0x0001, 0x0203, 0x0405})
+	assert.Equal(RegisterCodec{RegisterUint64, RegisterOrderCDAB}.Encode(0x0102030405),
+		[]uint16{0x0405, 0x0203, 0x0001, 0x0000})
+	assert.Equal(RegisterCodec{RegisterUint64, RegisterOrderBADC}.Encode(0x0102030405),
+		[]uint16{0x0000, 0x0100, 0x0302, 0x0504})
+	assert.Equal(RegisterCodec{RegisterUint64, RegisterOrderDCBA}.Encode(0x0102030405),
+		[]uint16{0x0504, 0x0302, 0x0100, 0x0000})
+	// 1.0 = 0x3FF0000000000000
+	assert.Equal(RegisterCodec{RegisterFloat64, RegisterOrderABCD}.Encode(1),
+		[]uint16{0x3FF0, 0x0000, 0x0000, 0x0000})
+	assert.Equal(RegisterCodec{RegisterFloat64, RegisterOrderDCBA}.Encode(1),
+		[]uint16{0x0000, 0x0000, 0x0000, 0xF03F})
+}
+
+func TestRegisterCodecDecode(t *testing.T) {
+	assert := assert.New(t)
+	v, err := RegisterCodec{RegisterFloat32, RegisterOrderCDAB}.Decode(
+		[]uint16{0xE979, 0x42F6})
+	assert.NoError(err)
+	assert.EqualValues(v, float32(123.456))
+	v, err = RegisterCodec{RegisterInt16, RegisterOrderBADC}.Decode(
+		[]uint16{0xFEFF, 0x1234})
+	assert.NoError(err)
+	assert.EqualValues(v, -2)
+	v, err = RegisterCodec{RegisterUint32, RegisterOrderDCBA}.Decode(
+		[]uint16{0x7856, 0x3412})
+	assert.NoError(err)
+	assert.EqualValues(v, 0x12345678)
+	v, err = RegisterCodec{RegisterInt64, RegisterOrderABCD}.Decode(
+		[]uint16{0xFFFF, 0xFFFF, 0xFFFF, 0xFFFE})
+	assert.NoError(err)
+	assert.EqualValues(v, -2)
+	_, err = RegisterCodec{RegisterFloat64, RegisterOrderABCD}.Decode(
+		[]uint16{0x3FF0, 0x0000, 0x0000})
+	assert.EqualError(err,
+		"core.RegisterCodec.Decode: require 4 registers, offer 3")
+	regs := []uint16{0xE979, 0x42F6}
+	RegisterCodec{RegisterFloat32, RegisterOrderDCBA}.Decode(regs)
+	assert.Equal(regs, []uint16{0xE979, 0x42F6})
+
+	types := []RegisterType{RegisterInt16, RegisterUint16, RegisterInt32,
+		RegisterUint32, RegisterFloat32, RegisterInt64, RegisterUint64,
+		RegisterFloat64}
+	orders := []RegisterOrder{RegisterOrderABCD, RegisterOrderCDAB,
+		RegisterOrderBADC, RegisterOrderDCBA}
+	for _, typ := range types {
+		for _, order := range orders {
+			c := RegisterCodec{typ, order}
+			for _, n := range []Number{0, 1, 100, 12345} {
+				v, err := c.Decode(c.Encode(n))
+				assert.NoError(err)
+				assert.Equal(n, v, "%v %v", typ, order)
+			}
+		}
+	}
+	c := RegisterCodec{RegisterFloat64, RegisterOrderBADC}
+	v, _ = c.Decode(c.Encode(math.Pi))
+	assert.EqualValues(v, math.Pi)
+}
+
+func TestByteArrayRegisters(t *testing.T) {
+	assert := assert.New(t)
+	var ba ByteArray
+	ba.AppendByte(0x01)
+	ba.AppendRegisters([]uint16{0x42F6, 0xE979})
+	assert.Equal(ba.ToString(), "[5]01 42 F6 E9 79")
+	regs, err := ba.Registers(1, 2)
+	assert.NoError(err)
+	assert.Equal(regs, []uint16{0x42F6, 0xE979})
+	regs, err = ba.Registers(0, 0)
+	assert.NoError(err)
+	assert.Equal(regs, []uint16{})
+	_, err = ba.Registers(2, 2)
+	assert.EqualError(err,
+		"core.ByteArray.Registers: Not enought data, require 4, offer 5-2=3")
+	_, err = ba.Registers(-1, 1)
+	assert.Error(err)
+	assert.NoError(ba.PutRegisters(3, []uint16{0xABCD}))
+	assert.Equal(ba.ToString(), "[5]01 42 F6 AB CD")
+	assert.Error(ba.PutRegisters(4, []uint16{0xABCD}))
+	assert.Equal(ba.ToString(), "[5]01 42 F6 AB CD")
+}
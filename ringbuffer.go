@@ -0,0 +1,173 @@
+package core
+
+import (
+	"bytes"
+	"errors"
+	"sync/atomic"
+)
+
+var ErrRingBufferFull = errors.New("core.RingBuffer: buffer full")
+
+// RingBuffer is a fixed-capacity byte FIFO for reassembling frames from a
+// stream. It is safe for one goroutine calling Write and one goroutine
+// calling the reading methods (Peek, Discard, Index, ...) at the same time;
+// Len and Free may be called from both. Any other concurrent use needs
+// external locking.
+type RingBuffer struct {
+	// read and write count the bytes consumed and produced, they only grow,
+	// read is updated by the consumer and write by the producer. They are the
+	// first fields to keep them 64-bit aligned for the atomic operations on
+	// 32-bit platforms.
+	read  uint64
+	write uint64
+	buf   []byte
+}
+
+func NewRingBuffer(capacity int) *RingBuffer {
+	if capacity <= 0 {
+		panic("core.NewRingBuffer: capacity must be positive")
+	}
+	return &RingBuffer{buf: make([]byte, capacity)}
+}
+
+func (r *RingBuffer) Cap() int {
+	return len(r.buf)
+}
+
+// Len returns the number of buffered bytes.
+func (r *RingBuffer) Len() int {
+	w := atomic.LoadUint64(&r.write)
+	return int(w - atomic.LoadUint64(&r.read))
+}
+
+func (r *RingBuffer) Free() int {
+	return len(r.buf) - r.Len()
+}
+
+// Write appends as much of p as fits and returns ErrRingBufferFull when not
+// all of p was written, so frames are never silently truncated.
+func (r *RingBuffer) Write(p []byte) (int, error) {
+	w := atomic.LoadUint64(&r.write)
+	free := len(r.buf) - int(w-atomic.LoadUint64(&r.read))
+	n := len(p)
+	if n > free {
+		n = free
+	}
+	start := int(w % uint64(len(r.buf)))
+	c := copy(r.buf[start:], p[:n])
+	copy(r.buf, p[c:n])
+	atomic.StoreUint64(&r.write, w+uint64(n))
+	if n < len(p) {
+		return n, ErrRingBufferFull
+	}
+	return n, nil
+}
+
+// regions returns the buffered bytes from offset on, in at most two parts.
+func (r *RingBuffer) regions(offset, n int) ([]byte, []byte) {
+	start := int((atomic.LoadUint64(&r.read) + uint64(offset)) %
+		uint64(len(r.buf)))
+	if start+n <= len(r.buf) {
+		return r.buf[start : start+n], nil
+	}
+	return r.buf[start:], r.buf[:start+n-len(r.buf)]
+}
+
+// Peek copies up to len(p) bytes from the start without consuming them.
+func (r *RingBuffer) Peek(p []byte) int {
+	n := r.Len()
+	if n > len(p) {
+		n = len(p)
+	}
+	a, b := r.regions(0, n)
+	copy(p, a)
+	copy(p[len(a):], b)
+	return n
+}
+
+// Read implements the io.Reader interface, it returns 0, nil when empty.
+func (r *RingBuffer) Read(p []byte) (int, error) {
+	n := r.Peek(p)
+	r.Discard(n)
+	return n, nil
+}
+
+// Discard consumes up to n bytes and returns the number consumed.
+func (r *RingBuffer) Discard(n int) int {
+	if l := r.Len(); n > l {
+		n = l
+	}
+	if n > 0 {
+		atomic.AddUint64(&r.read, uint64(n))
+	}
+	return n
+}
+
+// At returns the i-th buffered byte, i must be less than Len.
+func (r *RingBuffer) At(i int) byte {
+	if i < 0 || i >= r.Len() {
+		panic("core.RingBuffer.At: index out of range")
+	}
+	a, _ := r.regions(i, 1)
+	return a[0]
+}
+
+// Index returns the offset of the first instance of sep from the start, -1
+// if none.
+func (r *RingBuffer) Index(sep []byte) int {
+	return r.IndexFrom(sep, 0)
+}
+
+// IndexFrom is like Index but starts searching at offset from, so a search
+// can resume after the bytes already scanned.
+func (r *RingBuffer) IndexFrom(sep []byte, from int) int {
+	n := r.Len()
+	if from < 0 || from > n {
+		return -1
+	}
+	a, b := r.regions(from, n-from)
+	if i := bytes.Index(a, sep); i >= 0 {
+		return from + i
+	}
+	// matches across the wrap point
+	if len(b) > 0 && len(sep) > 1 {
+		k := len(sep) - 1
+		if k > len(a) {
+			k = len(a)
+		}
+		joint := append([]byte{}, a[len(a)-k:]...)
+		if len(b) < len(sep)-1 {
+			joint = append(joint, b...)
+		} else {
+			joint = append(joint, b[:len(sep)-1]...)
+		}
+		if i := bytes.Index(joint, sep); i >= 0 {
+			return from + len(a) - k + i
+		}
+	}
+	if i := bytes.Index(b, sep); i >= 0 {
+		return from + len(a) + i
+	}
+	return -1
+}
+
+// IndexPattern is like Index for a masked Pattern.
+func (r *RingBuffer) IndexPattern(p Pattern) int {
+	return r.ByteArray(0, r.Len()).IndexPattern(p)
+}
+
+// ByteArray returns a copy of n bytes starting at offset from the start,
+// without consuming them, nil when the range is not buffered.
+func (r *RingBuffer) ByteArray(offset, n int) ByteArray {
+	if offset < 0 || n < 0 || offset+n > r.Len() {
+		return nil
+	}
+	a, b := r.regions(offset, n)
+	ret := make(ByteArray, 0, n)
+	return append(append(ret, a...), b...)
+}
+
+// Reset discards all buffered bytes, it is a reading method.
+func (r *RingBuffer) Reset() {
+	atomic.StoreUint64(&r.read, atomic.LoadUint64(&r.write))
+}
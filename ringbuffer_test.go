@@ -0,0 +1,125 @@
+package core
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"runtime"
+	"sync"
+	"testing"
+)
+
+func TestRingBuffer(t *testing.T) {
+	assert := assert.New(t)
+	assert.Panics(func() { NewRingBuffer(0) })
+	r := NewRingBuffer(8)
+	assert.Equal(r.Cap(), 8)
+	assert.Equal(r.Len(), 0)
+	assert.Equal(r.Free(), 8)
+	n, err := r.Write([]byte{1, 2, 3, 4, 5})
+	assert.NoError(err)
+	assert.Equal(n, 5)
+	n, err = r.Write([]byte{6, 7, 8, 9, 10})
+	assert.Equal(err, ErrRingBufferFull)
+	assert.Equal(n, 3)
+	assert.Equal(r.Len(), 8)
+	assert.Equal(r.Free(), 0)
+	p := make([]byte, 3)
+	assert.Equal(r.Peek(p), 3)
+	assert.Equal(p, []byte{1, 2, 3})
+	assert.Equal(r.Discard(3), 3)
+	assert.Equal(r.At(0), byte(4))
+	// wraps around
+	n, err = r.Write([]byte{9, 10})
+	assert.NoError(err)
+	assert.Equal(n, 2)
+	assert.Equal(r.ByteArray(0, r.Len()), ByteArray{4, 5, 6, 7, 8, 9, 10})
+	assert.Equal(r.ByteArray(4, 3), ByteArray{8, 9, 10})
+	assert.Equal(r.ByteArray(4, 0), ByteArray{})
+	assert.Nil(r.ByteArray(4, 4))
+	assert.Nil(r.ByteArray(-1, 1))
+	assert.Equal(r.At(6), byte(10))
+	assert.Panics(func() { r.At(7) })
+	p = make([]byte, 10)
+	n, err = r.Read(p)
+	assert.NoError(err)
+	assert.Equal(n, 7)
+	assert.Equal(p[:n], []byte{4, 5, 6, 7, 8, 9, 10})
+	assert.Equal(r.Len(), 0)
+	assert.Equal(r.Discard(1), 0)
+	n, err = r.Read(p)
+	assert.NoError(err)
+	assert.Equal(n, 0)
+	r.Write([]byte{1, 2})
+	r.Reset()
+	assert.Equal(r.Len(), 0)
+}
+
+func TestRingBufferIndex(t *testing.T) {
+	assert := assert.New(t)
+	r := NewRingBuffer(8)
+	r.Write([]byte{0, 0, 0, 0, 0, 0})
+	r.Discard(6)
+	// the data starts at 6 and wraps at 8
+	r.Write([]byte{0xAA, 0x55, 1, 0xAA, 0x55, 2, 0xAA, 0x55})
+	assert.Equal(r.Index([]byte{0xAA, 0x55}), 0)
+	assert.Equal(r.IndexFrom([]byte{0xAA, 0x55}, 1), 3)
+	assert.Equal(r.IndexFrom([]byte{0xAA, 0x55}, 4), 6)
+	assert.Equal(r.Index([]byte{0x55, 1}), 1)
+	assert.Equal(r.Index([]byte{1, 0xAA, 0x55, 2}), 2)
+	assert.Equal(r.Index([]byte{3}), -1)
+	assert.Equal(r.IndexFrom([]byte{0xAA}, 9), -1)
+	assert.Equal(r.IndexPattern(MustParsePattern("55 ?? AA")), 1)
+	r.Discard(1)
+	assert.Equal(r.Index([]byte{0xAA, 0x55}), 2)
+	// compare with a linear buffer at every rotation
+	for shift := 0; shift < 8; shift++ {
+		r = NewRingBuffer(8)
+		r.Write(make([]byte, shift))
+		r.Discard(shift)
+		data := []byte{1, 2, 3, 1, 2, 3, 4, 1}
+		r.Write(data)
+		for _, sep := range [][]byte{{1}, {3, 4}, {4, 1}, {1, 2, 3, 4},
+			{2, 3, 1}, {3, 4, 1}, {4, 2}, {}} {
+			assert.Equal(r.Index(sep), bytes.Index(data, sep), "%d %v", shift, sep)
+		}
+	}
+}
+
+func TestRingBufferConcurrent(t *testing.T) {
+	assert := assert.New(t)
+	r := NewRingBuffer(7)
+	const total = 20000
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		var chunk [5]byte
+		for i := 0; i < total; {
+			n := 0
+			for ; n < len(chunk) && i+n < total; n++ {
+				chunk[n] = byte(i + n)
+			}
+			w, _ := r.Write(chunk[:n])
+			i += w
+			if w < n {
+				runtime.Gosched()
+			}
+		}
+	}()
+	got := make([]byte, 0, total)
+	p := make([]byte, 3)
+	for len(got) < total {
+		n, _ := r.Read(p)
+		got = append(got, p[:n]...)
+		if n == 0 {
+			runtime.Gosched()
+		}
+	}
+	wg.Wait()
+	for i, b := range got {
+		if b != byte(i) {
+			assert.Fail("data mismatch", "at %d", i)
+			break
+		}
+	}
+}
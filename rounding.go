@@ -0,0 +1,146 @@
+package core
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+type RoundingMode int
+
+const (
+	RoundHalfUp     RoundingMode = iota // ties toward +Inf
+	RoundHalfEven                       // ties to the even digit, bankers rounding
+	RoundHalfAway                       // ties away from zero
+	RoundTowardZero                     // truncate
+	RoundCeiling                        // toward +Inf
+	RoundFloor                          // toward -Inf
+)
+
+func (m RoundingMode) String() string {
+	switch m {
+	case RoundHalfUp:
+		return "HalfUp"
+	case RoundHalfEven:
+		return "HalfEven"
+	case RoundHalfAway:
+		return "HalfAway"
+	case RoundTowardZero:
+		return "TowardZero"
+	case RoundCeiling:
+		return "Ceiling"
+	case RoundFloor:
+		return "Floor"
+	}
+	return fmt.Sprintf("RoundingMode(%d)", int(m))
+}
+
+// Round rounds v to places decimal places using mode. Negative places round
+// to tens, hundreds and so on. Rounding works on the shortest decimal
+// representation of v, the one strconv prints, so 2.675 rounds to 2.68 with
+// RoundHalfUp although the nearest float64 is slightly below 2.675.
+func (v Number) Round(places int, mode RoundingMode) Number {
+	return roundDecimal(v, places, mode)
+}
+
+// RoundSignificant rounds v to digits significant digits using mode, NaN is
+// returned when digits is less than 1.
+func (v Number) RoundSignificant(digits int, mode RoundingMode) Number {
+	if digits < 1 {
+		return Number(math.NaN())
+	}
+	f := float64(v)
+	if f == 0 || math.IsNaN(f) || math.IsInf(f, 0) {
+		return v
+	}
+	if digits > maxRoundDigits {
+		digits = maxRoundDigits
+	}
+	_, exp := decimalDigits(f)
+	return roundDecimal(v, digits-exp, mode)
+}
+
+// maxRoundDigits is beyond the decimal digits of any float64, larger places
+// or digits are clamped to it so the digit arithmetic can not overflow.
+const maxRoundDigits = 400
+
+// decimalDigits returns the shortest decimal digits of |f| and the number of
+// digits before the decimal point, so |f| = 0.digits * 10^exp.
+func decimalDigits(f float64) (digits string, exp int) {
+	s := strconv.FormatFloat(math.Abs(f), 'e', -1, 64)
+	i := strings.IndexByte(s, 'e')
+	e, _ := strconv.Atoi(s[i+1:])
+	return strings.Replace(s[:i], ".", "", 1), e + 1
+}
+
+func roundDecimal(v Number, places int, mode RoundingMode) Number {
+	f := float64(v)
+	if f == 0 || math.IsNaN(f) || math.IsInf(f, 0) {
+		return v
+	}
+	if places > maxRoundDigits {
+		places = maxRoundDigits
+	} else if places < -maxRoundDigits {
+		places = -maxRoundDigits
+	}
+	digits, exp := decimalDigits(f)
+	n := exp + places // number of digits kept
+	if n >= len(digits) {
+		return v
+	}
+	kept, rest := "0", digits
+	if n > 0 {
+		kept, rest = digits[:n], digits[n:]
+	}
+	// cmp compares the discarded part with half a unit of the last kept
+	// digit, there is always a nonzero discarded digit.
+	cmp := -1
+	if n >= 0 {
+		switch {
+		case rest[0] > '5':
+			cmp = 1
+		case rest[0] == '5':
+			cmp = 0
+			if strings.TrimRight(rest[1:], "0") != "" {
+				cmp = 1
+			}
+		}
+	}
+	negative := f < 0
+	var up bool // increase the magnitude
+	switch mode {
+	case RoundHalfUp:
+		up = cmp > 0 || cmp == 0 && !negative
+	case RoundHalfEven:
+		up = cmp > 0 || cmp == 0 && (kept[len(kept)-1]-'0')%2 == 1
+	case RoundHalfAway:
+		up = cmp >= 0
+	case RoundTowardZero:
+		up = false
+	case RoundCeiling:
+		up = !negative
+	case RoundFloor:
+		up = negative
+	default:
+		panic("core.Number.Round: invalid rounding mode")
+	}
+	if up {
+		kept = incrementDecimal(kept)
+	}
+	r, _ := strconv.ParseFloat(kept+"e"+strconv.Itoa(-places), 64)
+	return Number(math.Copysign(r, f))
+}
+
+// incrementDecimal adds one to a string of decimal digits.
+func incrementDecimal(s string) string {
+	b := []byte(s)
+	for i := len(b) - 1; i >= 0; i-- {
+		if b[i] != '9' {
+			b[i]++
+			return string(b)
+		}
+		b[i] = '0'
+	}
+	return "1" + string(b)
+}
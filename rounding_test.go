@@ -0,0 +1,128 @@
+package core
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+var allRoundingModes = []RoundingMode{RoundHalfUp, RoundHalfEven,
+	RoundHalfAway, RoundTowardZero, RoundCeiling, RoundFloor}
+
+func TestRoundingModeString(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal(RoundHalfUp.String(), "HalfUp")
+	assert.Equal(RoundHalfEven.String(), "HalfEven")
+	assert.Equal(RoundHalfAway.String(), "HalfAway")
+	assert.Equal(RoundTowardZero.String(), "TowardZero")
+	assert.Equal(RoundCeiling.String(), "Ceiling")
+	assert.Equal(RoundFloor.String(), "Floor")
+	assert.Equal(RoundingMode(9).String(), "RoundingMode(9)")
+}
+
+func TestNumberRound(t *testing.T) {
+	assert := assert.New(t)
+	// results in the order of allRoundingModes
+	table := []struct {
+		v      Number
+		expect [6]Number
+	}{
+		{5.5, [6]Number{6, 6, 6, 5, 6, 5}},
+		{2.5, [6]Number{3, 2, 3, 2, 3, 2}},
+		{1.6, [6]Number{2, 2, 2, 1, 2, 1}},
+		{1.1, [6]Number{1, 1, 1, 1, 2, 1}},
+		{1.0, [6]Number{1, 1, 1, 1, 1, 1}},
+		{0.5, [6]Number{1, 0, 1, 0, 1, 0}},
+		{0.4, [6]Number{0, 0, 0, 0, 1, 0}},
+		{-0.4, [6]Number{0, 0, 0, 0, 0, -1}},
+		{-0.5, [6]Number{0, 0, -1, 0, 0, -1}},
+		{-1.0, [6]Number{-1, -1, -1, -1, -1, -1}},
+		{-1.1, [6]Number{-1, -1, -1, -1, -1, -2}},
+		{-1.6, [6]Number{-2, -2, -2, -1, -1, -2}},
+		{-2.5, [6]Number{-2, -2, -3, -2, -2, -3}},
+		{-5.5, [6]Number{-5, -6, -6, -5, -5, -6}},
+		{9.5, [6]Number{10, 10, 10, 9, 10, 9}},
+		{0.05, [6]Number{0, 0, 0, 0, 1, 0}},
+	}
+	for _, c := range table {
+		for i, m := range allRoundingModes {
+			assert.EqualValues(c.v.Round(0, m), c.expect[i], "%v %v", c.v, m)
+		}
+	}
+	// the sign of zero is kept
+	assert.True(math.Signbit(float64(Number(-0.4).Round(0, RoundHalfUp))))
+	assert.False(math.Signbit(float64(Number(0.4).Round(0, RoundHalfUp))))
+}
+
+func TestNumberRoundDecimal(t *testing.T) {
+	assert := assert.New(t)
+	// 2.675, 1.005 and 0.285 are slightly below the written value as float64
+	assert.EqualValues(Number(2.675).Round(2, RoundHalfUp), 2.68)
+	assert.EqualValues(Number(2.675).Round(2, RoundHalfAway), 2.68)
+	assert.EqualValues(Number(2.675).Round(2, RoundHalfEven), 2.68)
+	assert.EqualValues(Number(2.665).Round(2, RoundHalfEven), 2.66)
+	assert.EqualValues(Number(1.005).Round(2, RoundHalfUp), 1.01)
+	assert.EqualValues(Number(0.285).Round(2, RoundHalfAway), 0.29)
+	assert.EqualValues(Number(-2.675).Round(2, RoundHalfUp), -2.67)
+	assert.EqualValues(Number(-2.675).Round(2, RoundHalfAway), -2.68)
+	assert.EqualValues(Number(-2.675).Round(2, RoundHalfEven), -2.68)
+	assert.EqualValues(Number(1.2345).Round(3, RoundTowardZero), 1.234)
+	assert.EqualValues(Number(1.2341).Round(3, RoundCeiling), 1.235)
+	assert.EqualValues(Number(-1.2341).Round(3, RoundCeiling), -1.234)
+	assert.EqualValues(Number(-1.2341).Round(3, RoundFloor), -1.235)
+	a, b := Number(0.1), Number(0.2)
+	assert.EqualValues((a+b).Round(2, RoundCeiling), 0.31)
+	assert.EqualValues(Number(0.3).Round(2, RoundCeiling), 0.3)
+	assert.EqualValues(Number(9.999).Round(2, RoundHalfUp), 10)
+	assert.EqualValues(Number(99.95).Round(1, RoundHalfEven), 100)
+	// negative places
+	assert.EqualValues(Number(1250).Round(-2, RoundHalfEven), 1200)
+	assert.EqualValues(Number(1350).Round(-2, RoundHalfEven), 1400)
+	assert.EqualValues(Number(1250).Round(-2, RoundHalfUp), 1300)
+	assert.EqualValues(Number(1234.5).Round(-2, RoundHalfAway), 1200)
+	assert.EqualValues(Number(49).Round(-2, RoundHalfUp), 0)
+	assert.EqualValues(Number(50).Round(-2, RoundHalfUp), 100)
+	assert.EqualValues(Number(1).Round(-2, RoundCeiling), 100)
+	assert.EqualValues(Number(-1).Round(-2, RoundFloor), -100)
+	assert.EqualValues(Number(1).Round(-2, RoundFloor), 0)
+	// discarding more digits than the value has
+	assert.EqualValues(Number(0.004).Round(2, RoundHalfAway), 0)
+	assert.EqualValues(Number(0.004).Round(2, RoundCeiling), 0.01)
+	assert.EqualValues(Number(0.005).Round(2, RoundHalfAway), 0.01)
+	assert.EqualValues(Number(123).Round(-400, RoundHalfUp), 0)
+	// nothing to round
+	assert.EqualValues(Number(1.25).Round(2, RoundFloor), 1.25)
+	assert.EqualValues(Number(1e300).Round(20, RoundFloor), 1e300)
+	assert.EqualValues(Number(5e-324).Round(400, RoundFloor), 5e-324)
+	assert.EqualValues(Number(0).Round(2, RoundCeiling), 0)
+	// huge places must not overflow the digit count
+	assert.EqualValues(Number(123).Round(math.MaxInt64, RoundHalfUp), 123)
+	assert.EqualValues(Number(123).Round(math.MinInt64, RoundHalfUp), 0)
+	assert.True(Number(math.NaN()).Round(2, RoundHalfUp).IsNaN())
+	assert.True(Number(math.Inf(-1)).Round(2, RoundHalfUp).IsInf(-1))
+	assert.Panics(func() { Number(1.5).Round(0, RoundingMode(9)) })
+}
+
+func TestNumberRoundSignificant(t *testing.T) {
+	assert := assert.New(t)
+	assert.EqualValues(Number(123456).RoundSignificant(3, RoundHalfUp), 123000)
+	assert.EqualValues(Number(123556).RoundSignificant(3, RoundHalfUp), 124000)
+	assert.EqualValues(Number(0.00123456).RoundSignificant(2, RoundHalfUp),
+		0.0012)
+	assert.EqualValues(Number(0.0012500).RoundSignificant(2, RoundHalfEven),
+		0.0012)
+	assert.EqualValues(Number(0.0012500).RoundSignificant(2, RoundHalfAway),
+		0.0013)
+	assert.EqualValues(Number(-2.675).RoundSignificant(3, RoundHalfAway), -2.68)
+	assert.EqualValues(Number(999.5).RoundSignificant(3, RoundHalfEven), 1000)
+	assert.EqualValues(Number(1.5).RoundSignificant(1, RoundTowardZero), 1)
+	assert.EqualValues(Number(1.5).RoundSignificant(10, RoundTowardZero), 1.5)
+	assert.EqualValues(Number(1e-300).RoundSignificant(math.MaxInt64,
+		RoundTowardZero), 1e-300)
+	assert.EqualValues(Number(0).RoundSignificant(2, RoundCeiling), 0)
+	assert.True(Number(1).RoundSignificant(0, RoundHalfUp).IsNaN())
+	assert.True(Number(math.NaN()).RoundSignificant(2, RoundHalfUp).IsNaN())
+	for _, m := range allRoundingModes {
+		assert.EqualValues(Number(7).RoundSignificant(1, m), 7)
+	}
+}
@@ -0,0 +1,363 @@
+package core
+
+import (
+	"bytes"
+	"encoding"
+	"errors"
+	"fmt"
+	"github.com/newkedison/core/algorithm"
+	"sort"
+)
+
+// Scaler converts raw values, e.g. ADC counts, to engineering values and
+// back.
+type Scaler interface {
+	encoding.BinaryMarshaler
+	Scale(raw Number) Number
+	Inverse(value Number) (Number, error)
+}
+
+// LinearScaler computes raw * Gain + Offset.
+type LinearScaler struct {
+	Gain   Number
+	Offset Number
+}
+
+// RangeScaler maps [RawMin, RawMax] linearly to [Min, Max]. When Clamp is
+// set the input is limited to its range before scaling, in both directions.
+type RangeScaler struct {
+	RawMin Number
+	RawMax Number
+	Min    Number
+	Max    Number
+	Clamp  bool
+}
+
+type ScalePoint struct {
+	Raw   Number
+	Value Number
+}
+
+// TableScaler interpolates linearly between calibration points sorted by
+// Raw. Outside the table the first or last segment is extrapolated, or the
+// end value is used when Clamp is set.
+type TableScaler struct {
+	Points []ScalePoint
+	Clamp  bool
+}
+
+var scalerVersion int32 = 0
+
+const (
+	scalerKindLinear byte = 1
+	scalerKindRange  byte = 2
+	scalerKindTable  byte = 3
+)
+
+func NewLinearScaler(gain, offset Number) LinearScaler {
+	return LinearScaler{gain, offset}
+}
+
+func (s LinearScaler) Scale(raw Number) Number {
+	return raw*s.Gain + s.Offset
+}
+
+func (s LinearScaler) Inverse(value Number) (Number, error) {
+	if s.Gain == 0 {
+		return 0, errors.New("core.LinearScaler.Inverse: gain is 0")
+	}
+	return (value - s.Offset) / s.Gain, nil
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (s LinearScaler) MarshalBinary() ([]byte, error) {
+	buf := new(bytes.Buffer)
+	buf.Write(MarshalSimpleType(scalerVersion))
+	buf.Write(MarshalSimpleType(float64(s.Gain)))
+	buf.Write(MarshalSimpleType(float64(s.Offset)))
+	return algorithm.AppendCrc16(buf.Bytes()), nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (s *LinearScaler) UnmarshalBinary(data []byte) (err error) {
+	defer SetErrorWhenNotEnoughDataErrorPanic(
+		"core.LinearScaler.UnmarshalBinary", &err)()
+	data, err = checkScalerData("core.LinearScaler.UnmarshalBinary", data, 16)
+	if err != nil {
+		return err
+	}
+	offset := UnmashalSimpleType((*float64)(&s.Gain), data)
+	UnmashalSimpleType((*float64)(&s.Offset), data[offset:])
+	return nil
+}
+
+func NewRangeScaler(rawMin, rawMax, min, max Number, clamp bool) (
+	RangeScaler, error) {
+	s := RangeScaler{rawMin, rawMax, min, max, clamp}
+	if rawMin == rawMax {
+		return s, errors.New("core.NewRangeScaler: empty raw range")
+	}
+	return s, nil
+}
+
+func (s RangeScaler) Scale(raw Number) Number {
+	if s.Clamp {
+		raw = clampToRange(raw, s.RawMin, s.RawMax)
+	}
+	return s.Min + (raw-s.RawMin)*(s.Max-s.Min)/(s.RawMax-s.RawMin)
+}
+
+func (s RangeScaler) Inverse(value Number) (Number, error) {
+	if s.Min == s.Max {
+		return 0, errors.New("core.RangeScaler.Inverse: empty range")
+	}
+	if s.Clamp {
+		value = clampToRange(value, s.Min, s.Max)
+	}
+	return s.RawMin + (value-s.Min)*(s.RawMax-s.RawMin)/(s.Max-s.Min), nil
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (s RangeScaler) MarshalBinary() ([]byte, error) {
+	buf := new(bytes.Buffer)
+	buf.Write(MarshalSimpleType(scalerVersion))
+	buf.Write(MarshalSimpleType(float64(s.RawMin)))
+	buf.Write(MarshalSimpleType(float64(s.RawMax)))
+	buf.Write(MarshalSimpleType(float64(s.Min)))
+	buf.Write(MarshalSimpleType(float64(s.Max)))
+	buf.Write(marshalBool(s.Clamp))
+	return algorithm.AppendCrc16(buf.Bytes()), nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (s *RangeScaler) UnmarshalBinary(data []byte) (err error) {
+	defer SetErrorWhenNotEnoughDataErrorPanic(
+		"core.RangeScaler.UnmarshalBinary", &err)()
+	data, err = checkScalerData("core.RangeScaler.UnmarshalBinary", data, 33)
+	if err != nil {
+		return err
+	}
+	offset := UnmashalSimpleType((*float64)(&s.RawMin), data)
+	offset += UnmashalSimpleType((*float64)(&s.RawMax), data[offset:])
+	offset += UnmashalSimpleType((*float64)(&s.Min), data[offset:])
+	offset += UnmashalSimpleType((*float64)(&s.Max), data[offset:])
+	s.Clamp = data[offset] != 0
+	return nil
+}
+
+// NewTableScaler sorts a copy of points by Raw and checks there are at least
+// two points and no duplicated Raw values.
+func NewTableScaler(points []ScalePoint, clamp bool) (TableScaler, error) {
+	s := TableScaler{append([]ScalePoint(nil), points...), clamp}
+	sort.Slice(s.Points, func(i, j int) bool {
+		return s.Points[i].Raw < s.Points[j].Raw
+	})
+	if err := s.validate(); err != nil {
+		return TableScaler{}, err
+	}
+	return s, nil
+}
+
+func (s TableScaler) validate() error {
+	if len(s.Points) < 2 {
+		return errors.New("core.TableScaler: need at least 2 points")
+	}
+	for i := 1; i < len(s.Points); i++ {
+		if !(s.Points[i].Raw > s.Points[i-1].Raw) {
+			return fmt.Errorf(
+				"core.TableScaler: raw values not increasing at point %d", i)
+		}
+	}
+	return nil
+}
+
+func interpolate(x, x0, x1, y0, y1 Number) Number {
+	return y0 + (x-x0)*(y1-y0)/(x1-x0)
+}
+
+func (s TableScaler) Scale(raw Number) Number {
+	p := s.Points
+	switch len(p) {
+	case 0:
+		return raw
+	case 1:
+		return p[0].Value
+	}
+	i := sort.Search(len(p), func(i int) bool { return p[i].Raw >= raw })
+	switch {
+	case i == 0:
+		if s.Clamp {
+			return p[0].Value
+		}
+		i = 1
+	case i == len(p):
+		if s.Clamp {
+			return p[len(p)-1].Value
+		}
+		i = len(p) - 1
+	}
+	return interpolate(raw, p[i-1].Raw, p[i].Raw, p[i-1].Value, p[i].Value)
+}
+
+// Inverse requires the values of the table to be strictly monotonic.
+func (s TableScaler) Inverse(value Number) (Number, error) {
+	p := s.Points
+	if len(p) < 2 {
+		return 0, errors.New("core.TableScaler.Inverse: need at least 2 points")
+	}
+	increasing := p[1].Value > p[0].Value
+	for i := 1; i < len(p); i++ {
+		if (p[i].Value > p[i-1].Value) != increasing ||
+			p[i].Value == p[i-1].Value {
+			return 0, errors.New(
+				"core.TableScaler.Inverse: values are not strictly monotonic")
+		}
+	}
+	i := sort.Search(len(p), func(i int) bool {
+		if increasing {
+			return p[i].Value >= value
+		}
+		return p[i].Value <= value
+	})
+	switch {
+	case i == 0:
+		if s.Clamp {
+			return p[0].Raw, nil
+		}
+		i = 1
+	case i == len(p):
+		if s.Clamp {
+			return p[len(p)-1].Raw, nil
+		}
+		i = len(p) - 1
+	}
+	return interpolate(value, p[i-1].Value, p[i].Value, p[i-1].Raw, p[i].Raw),
+		nil
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (s TableScaler) MarshalBinary() ([]byte, error) {
+	buf := new(bytes.Buffer)
+	buf.Write(MarshalSimpleType(scalerVersion))
+	buf.Write(marshalBool(s.Clamp))
+	buf.Write(MarshalSimpleType(uint32(len(s.Points))))
+	for _, p := range s.Points {
+		buf.Write(MarshalSimpleType(float64(p.Raw)))
+		buf.Write(MarshalSimpleType(float64(p.Value)))
+	}
+	return algorithm.AppendCrc16(buf.Bytes()), nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (s *TableScaler) UnmarshalBinary(data []byte) (err error) {
+	defer SetErrorWhenNotEnoughDataErrorPanic(
+		"core.TableScaler.UnmarshalBinary", &err)()
+	var count uint32
+	CheckBufferSize(data, 4+1+4)
+	UnmashalSimpleType(&count, data[4+1:])
+	data, err = checkScalerData("core.TableScaler.UnmarshalBinary", data,
+		1+4+16*int(count))
+	if err != nil {
+		return err
+	}
+	clamp := data[0] != 0
+	offset := 1 + 4
+	points := make([]ScalePoint, count)
+	for i := range points {
+		offset += UnmashalSimpleType((*float64)(&points[i].Raw), data[offset:])
+		offset += UnmashalSimpleType((*float64)(&points[i].Value), data[offset:])
+	}
+	tmp := TableScaler{points, clamp}
+	if err := tmp.validate(); err != nil {
+		return err
+	}
+	*s = tmp
+	return nil
+}
+
+// checkScalerData verifies the version and CRC of a serialized scaler whose
+// payload is size bytes, and returns the payload.
+func checkScalerData(name string, data []byte, size int) ([]byte, error) {
+	CheckBufferSize(data, 4)
+	var ver int32
+	UnmashalSimpleType(&ver, data)
+	switch ver {
+	case 0:
+		CheckBufferSize(data, 4+size+2)
+		if !algorithm.VerifyCrc16(data[:4+size+2]) {
+			return nil, errors.New(name + ": CRC check fail")
+		}
+		return data[4 : 4+size], nil
+	}
+	return nil, errors.New(name + ": version error")
+}
+
+func marshalBool(b bool) []byte {
+	if b {
+		return []byte{1}
+	}
+	return []byte{0}
+}
+
+func clampToRange(v, a, b Number) Number {
+	if a > b {
+		a, b = b, a
+	}
+	if v < a {
+		return a
+	}
+	if v > b {
+		return b
+	}
+	return v
+}
+
+// MarshalScaler serializes one of the scalers of this package together with
+// its kind, so UnmarshalScaler can restore it without knowing the type.
+func MarshalScaler(s Scaler) ([]byte, error) {
+	var kind byte
+	switch s.(type) {
+	case LinearScaler, *LinearScaler:
+		kind = scalerKindLinear
+	case RangeScaler, *RangeScaler:
+		kind = scalerKindRange
+	case TableScaler, *TableScaler:
+		kind = scalerKindTable
+	default:
+		return nil, fmt.Errorf("core.MarshalScaler: unknown scaler %T", s)
+	}
+	data, err := MarshalObject(s)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{kind}, data...), nil
+}
+
+// UnmarshalScaler is the reverse of MarshalScaler, it returns the scaler and
+// the number of bytes consumed.
+func UnmarshalScaler(data []byte) (s Scaler, n int, err error) {
+	defer SetErrorWhenNotEnoughDataErrorPanic("core.Scaler", &err)()
+	CheckBufferSize(data, 1+4)
+	var dest interface {
+		Scaler
+		encoding.BinaryUnmarshaler
+	}
+	switch data[0] {
+	case scalerKindLinear:
+		dest = &LinearScaler{}
+	case scalerKindRange:
+		dest = &RangeScaler{}
+	case scalerKindTable:
+		dest = &TableScaler{}
+	default:
+		return nil, 0, fmt.Errorf(
+			"core.UnmarshalScaler: unknown scaler kind %d", data[0])
+	}
+	var size uint32
+	UnmashalSimpleType(&size, data[1:])
+	CheckBufferSize(data, int(size), 1+4)
+	if err := dest.UnmarshalBinary(data[1+4 : 1+4+int(size)]); err != nil {
+		return nil, 0, err
+	}
+	return dest, 1 + 4 + int(size), nil
+}
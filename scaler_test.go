@@ -0,0 +1,183 @@
+package core
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestLinearScaler(t *testing.T) {
+	assert := assert.New(t)
+	s := NewLinearScaler(0.5, -10)
+	assert.EqualValues(s.Scale(0), -10)
+	assert.EqualValues(s.Scale(100), 40)
+	v, err := s.Inverse(40)
+	assert.NoError(err)
+	assert.EqualValues(v, 100)
+	_, err = LinearScaler{0, 1}.Inverse(1)
+	assert.EqualError(err, "core.LinearScaler.Inverse: gain is 0")
+}
+
+func TestLinearScalerMarshalBinary(t *testing.T) {
+	assert := assert.New(t)
+	data, err := NewLinearScaler(2, 1).MarshalBinary()
+	assert.NoError(err)
+	assert.Equal(data, []byte{0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x40,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xF0, 0x3F,
+		0x11, 0xC8})
+	var s LinearScaler
+	assert.NoError(s.UnmarshalBinary(data))
+	assert.Equal(s, LinearScaler{2, 1})
+	assert.Error(s.UnmarshalBinary(data[:len(data)-1]))
+	data[5]++
+	assert.EqualError(s.UnmarshalBinary(data),
+		"core.LinearScaler.UnmarshalBinary: CRC check fail")
+	assert.EqualError(s.UnmarshalBinary([]byte{0x01, 0x00, 0x00, 0x00}),
+		"core.LinearScaler.UnmarshalBinary: version error")
+	assert.EqualError(s.UnmarshalBinary(nil),
+		"Unmashal core.LinearScaler.UnmarshalBinary fail: "+
+			"Not enought data, require 4, offer 0-0=0")
+}
+
+func TestRangeScaler(t *testing.T) {
+	assert := assert.New(t)
+	// 4-20mA on a 12-bit ADC mapped to 0-10 bar
+	s, err := NewRangeScaler(819, 4095, 0, 10, false)
+	assert.NoError(err)
+	assert.EqualValues(s.Scale(819), 0)
+	assert.EqualValues(s.Scale(4095), 10)
+	assert.InDelta(float64(s.Scale(2457)), 5, 1e-12)
+	assert.Less(float64(s.Scale(0)), 0.0)
+	v, err := s.Inverse(5)
+	assert.NoError(err)
+	assert.InDelta(float64(v), 2457, 1e-9)
+	s.Clamp = true
+	assert.EqualValues(s.Scale(0), 0)
+	assert.EqualValues(s.Scale(5000), 10)
+	v, err = s.Inverse(11)
+	assert.NoError(err)
+	assert.EqualValues(v, 4095)
+	s, err = NewRangeScaler(0, 100, 10, -10, true)
+	assert.NoError(err)
+	assert.EqualValues(s.Scale(25), 5)
+	assert.EqualValues(s.Scale(200), -10)
+	v, err = s.Inverse(-20)
+	assert.NoError(err)
+	assert.EqualValues(v, 100)
+	_, err = NewRangeScaler(1, 1, 0, 10, false)
+	assert.Error(err)
+	_, err = RangeScaler{0, 1, 5, 5, false}.Inverse(5)
+	assert.Error(err)
+}
+
+func TestRangeScalerMarshalBinary(t *testing.T) {
+	assert := assert.New(t)
+	s, _ := NewRangeScaler(819, 4095, 0, 10, true)
+	data, err := s.MarshalBinary()
+	assert.NoError(err)
+	assert.Len(data, 4+33+2)
+	var s2 RangeScaler
+	assert.NoError(s2.UnmarshalBinary(data))
+	assert.Equal(s, s2)
+	data[4+32] = 0
+	assert.Error(s2.UnmarshalBinary(data))
+}
+
+func TestTableScaler(t *testing.T) {
+	assert := assert.New(t)
+	s, err := NewTableScaler([]ScalePoint{
+		{100, 10}, {0, 0}, {200, 30}, {400, 40}}, false)
+	assert.NoError(err)
+	assert.Equal(s.Points[0], ScalePoint{0, 0})
+	assert.EqualValues(s.Scale(0), 0)
+	assert.EqualValues(s.Scale(50), 5)
+	assert.EqualValues(s.Scale(100), 10)
+	assert.EqualValues(s.Scale(150), 20)
+	assert.EqualValues(s.Scale(300), 35)
+	assert.EqualValues(s.Scale(400), 40)
+	assert.EqualValues(s.Scale(-100), -10)
+	assert.EqualValues(s.Scale(600), 50)
+	for _, raw := range []Number{-100, 0, 25, 100, 175, 250, 400, 500} {
+		v, err := s.Inverse(s.Scale(raw))
+		assert.NoError(err)
+		assert.InDelta(float64(raw), float64(v), 1e-9)
+	}
+	s.Clamp = true
+	assert.EqualValues(s.Scale(-100), 0)
+	assert.EqualValues(s.Scale(600), 40)
+	v, err := s.Inverse(100)
+	assert.NoError(err)
+	assert.EqualValues(v, 400)
+	v, err = s.Inverse(-1)
+	assert.NoError(err)
+	assert.EqualValues(v, 0)
+
+	s, err = NewTableScaler([]ScalePoint{{0, 100}, {10, 50}, {20, 0}}, false)
+	assert.NoError(err)
+	v, err = s.Inverse(75)
+	assert.NoError(err)
+	assert.EqualValues(v, 5)
+	v, err = s.Inverse(-50)
+	assert.NoError(err)
+	assert.EqualValues(v, 30)
+
+	s, err = NewTableScaler([]ScalePoint{{0, 0}, {10, 50}, {20, 0}}, false)
+	assert.NoError(err)
+	assert.EqualValues(s.Scale(15), 25)
+	_, err = s.Inverse(25)
+	assert.EqualError(err,
+		"core.TableScaler.Inverse: values are not strictly monotonic")
+
+	_, err = NewTableScaler([]ScalePoint{{0, 0}}, false)
+	assert.EqualError(err, "core.TableScaler: need at least 2 points")
+	_, err = NewTableScaler([]ScalePoint{{0, 0}, {1, 1}, {1, 2}}, false)
+	assert.EqualError(err,
+		"core.TableScaler: raw values not increasing at point 2")
+	assert.EqualValues(TableScaler{}.Scale(3), 3)
+	_, err = TableScaler{}.Inverse(3)
+	assert.Error(err)
+}
+
+func TestTableScalerMarshalBinary(t *testing.T) {
+	assert := assert.New(t)
+	s, _ := NewTableScaler([]ScalePoint{{0, 0}, {100, 10}, {200, 30}}, true)
+	data, err := s.MarshalBinary()
+	assert.NoError(err)
+	assert.Len(data, 4+1+4+3*16+2)
+	var s2 TableScaler
+	assert.NoError(s2.UnmarshalBinary(data))
+	assert.Equal(s, s2)
+	assert.Error(s2.UnmarshalBinary(data[:len(data)-1]))
+	assert.Error(s2.UnmarshalBinary(data[:6]))
+	bad := TableScaler{[]ScalePoint{{1, 0}, {0, 1}}, false}
+	data, _ = bad.MarshalBinary()
+	assert.Error(s2.UnmarshalBinary(data))
+	assert.Equal(s, s2)
+}
+
+func TestMarshalScaler(t *testing.T) {
+	assert := assert.New(t)
+	table, _ := NewTableScaler([]ScalePoint{{0, 0}, {100, 10}}, false)
+	scalers := []Scaler{NewLinearScaler(2, 1), &RangeScaler{0, 1, 0, 100, true},
+		table}
+	var buf []byte
+	for _, s := range scalers {
+		data, err := MarshalScaler(s)
+		assert.NoError(err)
+		buf = append(buf, data...)
+	}
+	offset := 0
+	for i := range scalers {
+		s, n, err := UnmarshalScaler(buf[offset:])
+		assert.NoError(err)
+		assert.EqualValues(s.Scale(50), scalers[i].Scale(50))
+		offset += n
+	}
+	assert.Equal(offset, len(buf))
+	_, err := MarshalScaler(nil)
+	assert.Error(err)
+	_, _, err = UnmarshalScaler([]byte{0x09, 0x00, 0x00, 0x00, 0x00})
+	assert.EqualError(err, "core.UnmarshalScaler: unknown scaler kind 9")
+	_, _, err = UnmarshalScaler(buf[:10])
+	assert.Error(err)
+}
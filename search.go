@@ -0,0 +1,240 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+func (ba ByteArray) Index(sep []byte) int {
+	return bytes.Index(ba, sep)
+}
+
+func (ba ByteArray) LastIndex(sep []byte) int {
+	return bytes.LastIndex(ba, sep)
+}
+
+// Count returns the number of non-overlapping instances of sep, like
+// bytes.Count it returns len(ba)+1 for an empty sep.
+func (ba ByteArray) Count(sep []byte) int {
+	return bytes.Count(ba, sep)
+}
+
+func (ba ByteArray) Contains(sep []byte) bool {
+	return bytes.Contains(ba, sep)
+}
+
+// Replace returns a copy of ba with the first n non-overlapping instances of
+// old replaced by new, all instances when n < 0.
+func (ba ByteArray) Replace(old, new []byte, n int) ByteArray {
+	return bytes.Replace(ba, old, new, n)
+}
+
+// Pattern is a byte pattern with wildcards, a byte b matches position i when
+// b&Mask[i] == Value[i]&Mask[i]. Mask bytes missing because Mask is nil or
+// shorter than Value are 0xFF, so Pattern{Value: v} matches v exactly, and
+// extra Mask bytes are ignored. An empty Pattern matches nowhere.
+type Pattern struct {
+	Value []byte
+	Mask  []byte
+}
+
+func (p Pattern) mask(i int) byte {
+	if i < len(p.Mask) {
+		return p.Mask[i]
+	}
+	return 0xFF
+}
+
+// NewPattern returns a Pattern matching exactly value.
+func NewPattern(value []byte) Pattern {
+	p := Pattern{append([]byte{}, value...), make([]byte, len(value))}
+	for i := range p.Mask {
+		p.Mask[i] = 0xFF
+	}
+	return p
+}
+
+// ParsePattern parses a pattern such as "AA 55 ?? ?? 0D". Each byte is two
+// characters, a hex digit or '?' for any nibble, so "3?" matches 0x30 to
+// 0x3F. Bytes may be separated by spaces, commas or dashes.
+func ParsePattern(s string) (Pattern, error) {
+	var p Pattern
+	digits := 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if strings.IndexByte(" \t\r\n,-", c) >= 0 {
+			if digits%2 == 1 {
+				return Pattern{}, fmt.Errorf(
+					"core.ParsePattern: incomplete byte at position %d", i)
+			}
+			continue
+		}
+		var v, m byte
+		switch {
+		case c == '?':
+		case isHexDigit(c):
+			n, _ := strconv.ParseUint(string(c), 16, 8)
+			v, m = byte(n), 0xF
+		default:
+			return Pattern{}, fmt.Errorf(
+				"core.ParsePattern: invalid character %q at position %d", c, i)
+		}
+		if digits%2 == 0 {
+			p.Value = append(p.Value, v<<4)
+			p.Mask = append(p.Mask, m<<4)
+		} else {
+			p.Value[len(p.Value)-1] |= v
+			p.Mask[len(p.Mask)-1] |= m
+		}
+		digits++
+	}
+	if digits%2 == 1 {
+		return Pattern{}, fmt.Errorf(
+			"core.ParsePattern: incomplete byte at position %d", len(s))
+	}
+	if digits == 0 {
+		return Pattern{}, fmt.Errorf("core.ParsePattern: empty pattern")
+	}
+	return p, nil
+}
+
+// MustParsePattern is like ParsePattern but panics on error, for patterns
+// known to be valid.
+func MustParsePattern(s string) Pattern {
+	p, err := ParsePattern(s)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+func (p Pattern) Len() int {
+	return len(p.Value)
+}
+
+func (p Pattern) String() string {
+	const digits = "0123456789ABCDEF"
+	parts := make([]string, len(p.Value))
+	for i, v := range p.Value {
+		b := []byte{'?', '?'}
+		if p.mask(i)&0xF0 == 0xF0 {
+			b[0] = digits[v>>4]
+		}
+		if p.mask(i)&0x0F == 0x0F {
+			b[1] = digits[v&0xF]
+		}
+		parts[i] = string(b)
+	}
+	return strings.Join(parts, " ")
+}
+
+// Match reports whether data starts with p.
+func (p Pattern) Match(data []byte) bool {
+	if len(p.Value) == 0 || len(data) < len(p.Value) {
+		return false
+	}
+	for i := len(p.Value) - 1; i >= 0; i-- {
+		if mask := p.mask(i); p.Value[i]&mask != data[i]&mask {
+			return false
+		}
+	}
+	return true
+}
+
+// shifts returns the Boyer-Moore-Horspool bad character table. For the
+// forward search shift[c] is the distance from the last position to the
+// nearest earlier position which can match c, for the backward search the
+// distance from the first position to the nearest later one.
+func (p Pattern) shifts(backward bool) *[256]int {
+	m := len(p.Value)
+	var shift [256]int
+	for c := range shift {
+		shift[c] = m
+	}
+	for j := 1; j < m; j++ {
+		i := m - 1 - j
+		if backward {
+			i = j
+		}
+		mask := p.mask(i)
+		value := p.Value[i] & mask
+		for c := range shift {
+			if shift[c] == m && byte(c)&mask == value {
+				shift[c] = j
+			}
+		}
+	}
+	return &shift
+}
+
+func (p Pattern) index(data []byte, shift *[256]int) int {
+	m := len(p.Value)
+	for i := 0; i+m <= len(data); i += shift[data[i+m-1]] {
+		if p.Match(data[i:]) {
+			return i
+		}
+	}
+	return -1
+}
+
+// IndexPattern returns the index of the first match of p, -1 if none. The
+// search uses the Boyer-Moore-Horspool algorithm extended to masks, its
+// speed depends on how selective the last bytes of p are.
+func (ba ByteArray) IndexPattern(p Pattern) int {
+	if len(p.Value) == 0 {
+		return -1
+	}
+	return p.index(ba, p.shifts(false))
+}
+
+func (ba ByteArray) LastIndexPattern(p Pattern) int {
+	m := len(p.Value)
+	if m == 0 {
+		return -1
+	}
+	shift := p.shifts(true)
+	for i := len(ba) - m; i >= 0; i -= shift[ba[i]] {
+		if p.Match(ba[i:]) {
+			return i
+		}
+	}
+	return -1
+}
+
+// FindAllPattern returns the indexes of the first n non-overlapping matches
+// of p, all matches when n < 0.
+func (ba ByteArray) FindAllPattern(p Pattern, n int) []int {
+	var ret []int
+	if len(p.Value) == 0 {
+		return ret
+	}
+	shift := p.shifts(false)
+	for start := 0; n < 0 || len(ret) < n; {
+		i := p.index(ba[start:], shift)
+		if i < 0 {
+			break
+		}
+		ret = append(ret, start+i)
+		start += i + len(p.Value)
+	}
+	return ret
+}
+
+func (ba ByteArray) CountPattern(p Pattern) int {
+	return len(ba.FindAllPattern(p, -1))
+}
+
+// ReplacePattern returns a copy of ba with the first n non-overlapping
+// matches of p replaced by new, all matches when n < 0.
+func (ba ByteArray) ReplacePattern(p Pattern, new []byte, n int) ByteArray {
+	ret := make(ByteArray, 0, len(ba))
+	start := 0
+	for _, i := range ba.FindAllPattern(p, n) {
+		ret = append(ret, ba[start:i]...)
+		ret = append(ret, new...)
+		start = i + len(p.Value)
+	}
+	return append(ret, ba[start:]...)
+}
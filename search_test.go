@@ -0,0 +1,173 @@
+package core
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"testing"
+)
+
+func TestByteArrayIndex(t *testing.T) {
+	assert := assert.New(t)
+	ba := ByteArray{0xAA, 0x55, 1, 0xAA, 0x55, 0xAA, 0x55, 0xAA}
+	assert.Equal(ba.Index([]byte{0xAA, 0x55}), 0)
+	assert.Equal(ba.LastIndex([]byte{0xAA, 0x55}), 5)
+	assert.Equal(ba.Index([]byte{0x55, 0xAA, 0x55}), 4)
+	assert.Equal(ba.Index([]byte{2}), -1)
+	assert.Equal(ba.Count([]byte{0xAA, 0x55}), 3)
+	assert.Equal(ba.Count([]byte{0xAA, 0x55, 0xAA}), 1)
+	assert.True(ba.Contains([]byte{1, 0xAA}))
+	assert.False(ba.Contains([]byte{1, 1}))
+	assert.Equal(ba.Replace([]byte{0xAA, 0x55}, []byte{0}, -1),
+		ByteArray{0, 1, 0, 0, 0xAA})
+	assert.Equal(ba.Replace([]byte{0xAA, 0x55}, nil, 1),
+		ByteArray{1, 0xAA, 0x55, 0xAA, 0x55, 0xAA})
+	assert.Equal(ba[0], byte(0xAA))
+}
+
+func TestParsePattern(t *testing.T) {
+	assert := assert.New(t)
+	p, err := ParsePattern("AA 55 ?? ?? 0d")
+	assert.NoError(err)
+	assert.Equal(p, Pattern{[]byte{0xAA, 0x55, 0, 0, 0x0D},
+		[]byte{0xFF, 0xFF, 0, 0, 0xFF}})
+	assert.Equal(p.Len(), 5)
+	assert.Equal(p.String(), "AA 55 ?? ?? 0D")
+	p, err = ParsePattern("3?,?F-AA55")
+	assert.NoError(err)
+	assert.Equal(p, Pattern{[]byte{0x30, 0x0F, 0xAA, 0x55},
+		[]byte{0xF0, 0x0F, 0xFF, 0xFF}})
+	assert.Equal(p.String(), "3? ?F AA 55")
+	assert.Equal(NewPattern([]byte{1, 2}).String(), "01 02")
+
+	_, err = ParsePattern("AA 5 55")
+	assert.EqualError(err, "core.ParsePattern: incomplete byte at position 4")
+	_, err = ParsePattern("AA5")
+	assert.EqualError(err, "core.ParsePattern: incomplete byte at position 3")
+	_, err = ParsePattern("AA G5")
+	assert.EqualError(err, "core.ParsePattern: invalid character 'G' at position 3")
+	_, err = ParsePattern(" ")
+	assert.EqualError(err, "core.ParsePattern: empty pattern")
+	assert.Panics(func() { MustParsePattern("*") })
+}
+
+func TestPatternMatch(t *testing.T) {
+	assert := assert.New(t)
+	p := MustParsePattern("AA ?5")
+	assert.True(p.Match([]byte{0xAA, 0x05}))
+	assert.True(p.Match([]byte{0xAA, 0xF5, 0}))
+	assert.False(p.Match([]byte{0xAA, 0x06}))
+	assert.False(p.Match([]byte{0xAA}))
+	assert.False(Pattern{}.Match([]byte{0xAA}))
+	// a nil or short mask matches the remaining bytes exactly
+	p = Pattern{Value: []byte{0xAA, 0x55}}
+	assert.True(p.Match([]byte{0xAA, 0x55}))
+	assert.False(p.Match([]byte{0xAA, 0x56}))
+	assert.Equal(p.String(), "AA 55")
+	p = Pattern{Value: []byte{0xAA, 0x55}, Mask: []byte{0x00}}
+	assert.True(p.Match([]byte{0x00, 0x55}))
+	assert.False(p.Match([]byte{0x00, 0x56}))
+	assert.Equal(p.String(), "?? 55")
+	p = Pattern{Value: []byte{0xAA}, Mask: []byte{0xFF, 0x00}}
+	assert.True(p.Match([]byte{0xAA}))
+	ba := ByteArray{0, 0xAA, 0x55, 0xAA, 0x55}
+	p = Pattern{Value: []byte{0xAA, 0x55}}
+	assert.Equal(ba.IndexPattern(p), 1)
+	assert.Equal(ba.LastIndexPattern(p), 3)
+	assert.Equal(ba.CountPattern(p), 2)
+}
+
+func TestIndexPattern(t *testing.T) {
+	assert := assert.New(t)
+	ba := ByteArray{0, 0xAA, 0x55, 1, 2, 0x0D, 0xAA, 0x55, 3, 4, 0x0D, 0xAA}
+	p := MustParsePattern("AA 55 ?? ?? 0D")
+	assert.Equal(ba.IndexPattern(p), 1)
+	assert.Equal(ba.LastIndexPattern(p), 6)
+	assert.Equal(ba.FindAllPattern(p, -1), []int{1, 6})
+	assert.Equal(ba.FindAllPattern(p, 1), []int{1})
+	assert.Nil(ba.FindAllPattern(p, 0))
+	assert.Equal(ba.CountPattern(p), 2)
+	assert.Equal(ba.IndexPattern(MustParsePattern("0D AA 56")), -1)
+	assert.Equal(ba.LastIndexPattern(MustParsePattern("0D AA 56")), -1)
+	assert.Equal(ba.IndexPattern(MustParsePattern("0D AA")), 5)
+	assert.Equal(ba.LastIndexPattern(MustParsePattern("0D AA")), 10)
+	assert.Equal(ba.IndexPattern(MustParsePattern("??")), 0)
+	assert.Equal(ba.LastIndexPattern(MustParsePattern("??")), 11)
+	assert.Equal(ba.CountPattern(MustParsePattern("?? ?? ?? ?? ??")), 2)
+	assert.Equal(ba[:3].IndexPattern(p), -1)
+	assert.Equal(ba.IndexPattern(Pattern{}), -1)
+	assert.Equal(ba.LastIndexPattern(Pattern{}), -1)
+	assert.Equal(ba.CountPattern(Pattern{}), 0)
+	// overlapping candidates
+	ba = ByteArray{0xAA, 0xAA, 0xAA, 0xAA, 0xAA}
+	assert.Equal(ba.FindAllPattern(MustParsePattern("AA AA"), -1), []int{0, 2})
+	assert.Equal(ba.LastIndexPattern(MustParsePattern("AA AA")), 3)
+}
+
+func TestReplacePattern(t *testing.T) {
+	assert := assert.New(t)
+	ba := ByteArray{0x10, 0xAA, 0x11, 0xAA, 0x12, 0x13}
+	p := MustParsePattern("1? AA")
+	assert.Equal(ba.ReplacePattern(p, []byte{0}, -1), ByteArray{0, 0, 0x12, 0x13})
+	assert.Equal(ba.ReplacePattern(p, []byte{1, 2, 3}, 1),
+		ByteArray{1, 2, 3, 0x11, 0xAA, 0x12, 0x13})
+	assert.Equal(ba.ReplacePattern(p, nil, 0), ba)
+	assert.Equal(ba.ReplacePattern(Pattern{}, nil, -1), ba)
+	assert.Equal(ba[0], byte(0x10))
+}
+
+func bruteForceFindAll(data []byte, p Pattern) []int {
+	var ret []int
+	for i := 0; i+p.Len() <= len(data); i++ {
+		if p.Match(data[i:]) {
+			ret = append(ret, i)
+			i += p.Len() - 1
+		}
+	}
+	return ret
+}
+
+func TestIndexPatternRandom(t *testing.T) {
+	assert := assert.New(t)
+	r := rand.New(rand.NewSource(1))
+	data := make(ByteArray, 4096)
+	for i := range data {
+		// a small alphabet gives many partial matches
+		data[i] = byte(r.Intn(4)) * 0x11
+	}
+	for n := 0; n < 500; n++ {
+		p := Pattern{make([]byte, 1+r.Intn(6)), nil}
+		p.Mask = make([]byte, p.Len())
+		for i := range p.Value {
+			p.Value[i] = byte(r.Intn(4)) * 0x11
+			p.Mask[i] = []byte{0xFF, 0xFF, 0xF0, 0x0F, 0}[r.Intn(5)]
+		}
+		expect := bruteForceFindAll(data, p)
+		assert.Equal(data.FindAllPattern(p, -1), expect, p.String())
+		if len(expect) == 0 {
+			assert.Equal(data.IndexPattern(p), -1)
+			assert.Equal(data.LastIndexPattern(p), -1)
+			continue
+		}
+		assert.Equal(data.IndexPattern(p), expect[0], p.String())
+		last := -1
+		for i := len(data) - p.Len(); i >= 0; i-- {
+			if p.Match(data[i:]) {
+				last = i
+				break
+			}
+		}
+		assert.Equal(data.LastIndexPattern(p), last, p.String())
+	}
+}
+
+func BenchmarkIndexPattern(b *testing.B) {
+	data := make(ByteArray, 4<<20)
+	for i := range data {
+		data[i] = byte(i * 7)
+	}
+	p := MustParsePattern("AA 55 ?? ?? 0D 0A")
+	b.SetBytes(int64(len(data)))
+	for i := 0; i < b.N; i++ {
+		data.IndexPattern(p)
+	}
+}
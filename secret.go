@@ -0,0 +1,97 @@
+package core
+
+import (
+	"crypto/subtle"
+	"fmt"
+)
+
+// Equal compares ba and other in constant time, the time depends only on
+// the lengths, so it can compare MACs and keys. Use bytes.Equal for
+// ordinary data, it is faster.
+func (ba ByteArray) Equal(other []byte) bool {
+	return subtle.ConstantTimeCompare(ba, other) == 1
+}
+
+// Wipe zeroes the whole backing array of ba up to its capacity, including
+// the bytes beyond its length left by earlier reslicing.
+func (ba ByteArray) Wipe() {
+	b := ba[:cap(ba)]
+	for i := range b {
+		b[i] = 0
+	}
+}
+
+const redacted = "<redacted>"
+
+// SecretByteArray holds sensitive data such as keys. All its formatters
+// redact the content, so it does not leak into logs; Bytes reveals it
+// explicitly.
+type SecretByteArray struct {
+	data ByteArray
+}
+
+// NewSecretByteArray copies data, the caller should wipe its own copy.
+func NewSecretByteArray(data []byte) SecretByteArray {
+	return SecretByteArray{append(ByteArray{}, data...)}
+}
+
+// Bytes returns the content, sharing the memory of s.
+func (s SecretByteArray) Bytes() ByteArray {
+	return s.data
+}
+
+func (s SecretByteArray) Len() int {
+	return len(s.data)
+}
+
+// Equal compares in constant time like ByteArray.Equal.
+func (s SecretByteArray) Equal(other []byte) bool {
+	return s.data.Equal(other)
+}
+
+func (s SecretByteArray) Wipe() {
+	s.data.Wipe()
+}
+
+// ToStringEx is like ByteArray.ToStringEx with the bytes redacted, only the
+// length is shown.
+func (s SecretByteArray) ToStringEx(
+	withLen bool, sep string, prefix string, suffix string) string {
+	if withLen {
+		return fmt.Sprintf("[%d]%s", len(s.data), redacted)
+	}
+	return redacted
+}
+
+func (s SecretByteArray) ToString() string {
+	return s.ToStringEx(true, " ", "", "")
+}
+
+func (s SecretByteArray) String() string {
+	return s.ToString()
+}
+
+func (s SecretByteArray) GoString() string {
+	return "core.SecretByteArray{" + redacted + "}"
+}
+
+// Format implements the fmt.Formatter interface, every verb is redacted.
+func (s SecretByteArray) Format(f fmt.State, verb rune) {
+	if verb == 'v' && f.Flag('#') {
+		fmt.Fprint(f, s.GoString())
+		return
+	}
+	fmt.Fprint(f, s.ToString())
+}
+
+// MarshalJSON implements the json.Marshaler interface with a redacted
+// string.
+func (s SecretByteArray) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + redacted + `"`), nil
+}
+
+// MarshalText implements the encoding.TextMarshaler interface with the
+// redacted text.
+func (s SecretByteArray) MarshalText() ([]byte, error) {
+	return []byte(redacted), nil
+}
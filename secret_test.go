@@ -0,0 +1,58 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestByteArrayEqual(t *testing.T) {
+	assert := assert.New(t)
+	assert.True(ByteArray{1, 2, 3}.Equal([]byte{1, 2, 3}))
+	assert.False(ByteArray{1, 2, 3}.Equal([]byte{1, 2, 4}))
+	assert.False(ByteArray{1, 2, 3}.Equal([]byte{1, 2}))
+	assert.True(ByteArray{}.Equal(nil))
+}
+
+func TestByteArrayWipe(t *testing.T) {
+	assert := assert.New(t)
+	backing := []byte{1, 2, 3, 4, 5}
+	ba := ByteArray(backing[:2])
+	ba.Wipe()
+	assert.Equal(backing, []byte{0, 0, 0, 0, 0})
+	assert.Len(ba, 2)
+	ByteArray(nil).Wipe()
+}
+
+func TestSecretByteArray(t *testing.T) {
+	assert := assert.New(t)
+	key := []byte{0xDE, 0xAD, 0xBE, 0xEF}
+	s := NewSecretByteArray(key)
+	key[0] = 0
+	assert.Equal(s.Bytes(), ByteArray{0xDE, 0xAD, 0xBE, 0xEF})
+	assert.Equal(s.Len(), 4)
+	assert.True(s.Equal([]byte{0xDE, 0xAD, 0xBE, 0xEF}))
+	assert.False(s.Equal(key))
+
+	assert.Equal(s.ToString(), "[4]<redacted>")
+	assert.Equal(s.ToStringEx(false, ",", "0x", ""), "<redacted>")
+	assert.Equal(s.String(), "[4]<redacted>")
+	for _, format := range []string{"%v", "%s", "%x", "%X", "%d", "%+v", "%q"} {
+		assert.Equal(fmt.Sprintf(format, s), "[4]<redacted>", format)
+	}
+	assert.Equal(fmt.Sprintf("%#v", s), "core.SecretByteArray{<redacted>}")
+	assert.Equal(fmt.Sprint(struct{ Key SecretByteArray }{s}),
+		"{[4]<redacted>}")
+	data, err := json.Marshal(map[string]interface{}{"key": s})
+	assert.NoError(err)
+	var decoded map[string]string
+	assert.NoError(json.Unmarshal(data, &decoded))
+	assert.Equal(decoded, map[string]string{"key": "<redacted>"})
+	text, err := s.MarshalText()
+	assert.NoError(err)
+	assert.Equal(string(text), "<redacted>")
+
+	s.Wipe()
+	assert.Equal(s.Bytes(), ByteArray{0, 0, 0, 0})
+}
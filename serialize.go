@@ -85,6 +85,10 @@ func MarshalSimpleType(d interface{}) []byte {
 		return MarshalSimpleType(math.Float32bits(v))
 	case float64:
 		return MarshalSimpleType(math.Float64bits(v))
+	case Float16:
+		return MarshalSimpleType(uint16(v))
+	case BFloat16:
+		return MarshalSimpleType(uint16(v))
 	}
 	panic("MarshalSimpleType: Unknown type")
 }
@@ -141,6 +145,14 @@ func UnmashalSimpleType(p interface{}, data []byte) int {
 			CheckBufferSize(data, 8)
 			*v = math.Float64frombits(defaultByteOrder.Uint64(data))
 			return 8
+		case *Float16:
+			CheckBufferSize(data, 2)
+			*v = Float16(defaultByteOrder.Uint16(data))
+			return 2
+		case *BFloat16:
+			CheckBufferSize(data, 2)
+			*v = BFloat16(defaultByteOrder.Uint16(data))
+			return 2
 		}
 	}
 	panic("UnmarshalSimpleType: Unknown type")
@@ -1,6 +1,7 @@
 package core
 
 import (
+	"encoding/binary"
 	"errors"
 	"github.com/stretchr/testify/assert"
 	"math"
@@ -182,6 +183,7 @@ func (myByteOrder) String() string { return "myByteOrder" }
 func TestSetByteOrder(t *testing.T) {
 	assert := assert.New(t)
 	SetByteOrder(myByteOrder{})
+	defer SetByteOrder(binary.LittleEndian)
 	assert.Equal(MarshalSimpleType(uint32(0)), []byte{0xAA, 0xAA, 0xAA, 0x00})
 	var v uint32
 	assert.EqualValues(UnmashalSimpleType(&v, []byte{0xAA, 0xFF, 0xAA, 0xFF}), 4)
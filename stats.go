@@ -0,0 +1,211 @@
+package core
+
+import (
+	"bytes"
+	"errors"
+	"github.com/newkedison/core/algorithm"
+	"math"
+	"sort"
+)
+
+// Accumulator keeps streaming statistics of Numbers: count, min, max, a
+// compensated sum and Welford's running mean and variance. The zero value is
+// ready to use. Accumulators filled by different workers can be combined
+// with Merge.
+type Accumulator struct {
+	count uint64
+	mean  float64
+	m2    float64
+	min   float64
+	max   float64
+	sum   float64
+	comp  float64
+}
+
+func (a *Accumulator) Add(values ...Number) {
+	for _, n := range values {
+		v := float64(n)
+		if a.count == 0 {
+			a.min, a.max = v, v
+		} else {
+			a.min = math.Min(a.min, v)
+			a.max = math.Max(a.max, v)
+		}
+		a.count++
+		d := v - a.mean
+		a.mean += d / float64(a.count)
+		a.m2 += d * (v - a.mean)
+		a.addSum(v)
+	}
+}
+
+// addSum is Kahan summation in Neumaier's variant, which also handles
+// addends larger than the running sum.
+func (a *Accumulator) addSum(v float64) {
+	t := a.sum + v
+	if math.Abs(a.sum) >= math.Abs(v) {
+		a.comp += (a.sum - t) + v
+	} else {
+		a.comp += (v - t) + a.sum
+	}
+	a.sum = t
+}
+
+// Merge adds the values collected by o to a.
+func (a *Accumulator) Merge(o Accumulator) {
+	if o.count == 0 {
+		return
+	}
+	if a.count == 0 {
+		*a = o
+		return
+	}
+	n := float64(a.count + o.count)
+	d := o.mean - a.mean
+	a.mean += d * float64(o.count) / n
+	a.m2 += o.m2 + d*d*float64(a.count)*float64(o.count)/n
+	a.min = math.Min(a.min, o.min)
+	a.max = math.Max(a.max, o.max)
+	a.count += o.count
+	a.addSum(o.sum)
+	a.comp += o.comp
+}
+
+func (a *Accumulator) Reset() {
+	*a = Accumulator{}
+}
+
+func (a Accumulator) Count() uint64 {
+	return a.count
+}
+
+func (a Accumulator) Sum() Number {
+	return Number(a.sum + a.comp)
+}
+
+// Mean returns NaN when no value was added, like Min, Max and the variance
+// functions.
+func (a Accumulator) Mean() Number {
+	if a.count == 0 {
+		return Number(math.NaN())
+	}
+	return Number(a.mean)
+}
+
+func (a Accumulator) Min() Number {
+	if a.count == 0 {
+		return Number(math.NaN())
+	}
+	return Number(a.min)
+}
+
+func (a Accumulator) Max() Number {
+	if a.count == 0 {
+		return Number(math.NaN())
+	}
+	return Number(a.max)
+}
+
+// Variance returns the population variance.
+func (a Accumulator) Variance() Number {
+	if a.count == 0 {
+		return Number(math.NaN())
+	}
+	return Number(a.m2 / float64(a.count))
+}
+
+// SampleVariance returns the unbiased sample variance, NaN for less than
+// two values.
+func (a Accumulator) SampleVariance() Number {
+	if a.count < 2 {
+		return Number(math.NaN())
+	}
+	return Number(a.m2 / float64(a.count-1))
+}
+
+func (a Accumulator) StdDev() Number {
+	return Number(math.Sqrt(float64(a.Variance())))
+}
+
+func (a Accumulator) SampleStdDev() Number {
+	return Number(math.Sqrt(float64(a.SampleVariance())))
+}
+
+var accumulatorVersion int32 = 0
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (a Accumulator) MarshalBinary() ([]byte, error) {
+	buf := new(bytes.Buffer)
+	buf.Write(MarshalSimpleType(accumulatorVersion))
+	buf.Write(MarshalSimpleType(a.count))
+	for _, f := range []float64{a.mean, a.m2, a.min, a.max, a.sum, a.comp} {
+		buf.Write(MarshalSimpleType(f))
+	}
+	return algorithm.AppendCrc16(buf.Bytes()), nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (a *Accumulator) UnmarshalBinary(data []byte) (err error) {
+	defer SetErrorWhenNotEnoughDataErrorPanic(
+		"core.Accumulator.UnmarshalBinary", &err)()
+	CheckBufferSize(data, 4)
+	var ver int32
+	offset := UnmashalSimpleType(&ver, data)
+	switch ver {
+	case 0:
+		CheckBufferSize(data, 4+8+6*8+2)
+		if !algorithm.VerifyCrc16(data[:4+8+6*8+2]) {
+			return errors.New("core.Accumulator.UnmarshalBinary: CRC check fail")
+		}
+		var tmp Accumulator
+		offset += UnmashalSimpleType(&tmp.count, data[offset:])
+		for _, p := range []*float64{&tmp.mean, &tmp.m2, &tmp.min, &tmp.max,
+			&tmp.sum, &tmp.comp} {
+			offset += UnmashalSimpleType(p, data[offset:])
+		}
+		*a = tmp
+	default:
+		return errors.New("core.Accumulator.UnmarshalBinary: version error")
+	}
+	return nil
+}
+
+func sortedNumbers(values []Number) []Number {
+	s := append([]Number(nil), values...)
+	sort.Slice(s, func(i, j int) bool { return s[i] < s[j] })
+	return s
+}
+
+func percentileSorted(s []Number, p float64) Number {
+	if len(s) == 0 || !(p >= 0 && p <= 100) {
+		return Number(math.NaN())
+	}
+	pos := p / 100 * float64(len(s)-1)
+	i := int(pos)
+	if i >= len(s)-1 {
+		return s[len(s)-1]
+	}
+	frac := Number(pos - float64(i))
+	return s[i] + (s[i+1]-s[i])*frac
+}
+
+// Percentile returns the p-th percentile (0 <= p <= 100) of values using
+// linear interpolation between the closest ranks. values is not modified.
+// NaN is returned for an empty slice or p out of range.
+func Percentile(values []Number, p float64) Number {
+	return percentileSorted(sortedNumbers(values), p)
+}
+
+// Percentiles is like Percentile for several p, sorting values only once.
+func Percentiles(values []Number, ps ...float64) []Number {
+	s := sortedNumbers(values)
+	ret := make([]Number, len(ps))
+	for i, p := range ps {
+		ret[i] = percentileSorted(s, p)
+	}
+	return ret
+}
+
+func Median(values []Number) Number {
+	return Percentile(values, 50)
+}
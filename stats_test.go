@@ -0,0 +1,126 @@
+package core
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+func TestAccumulator(t *testing.T) {
+	assert := assert.New(t)
+	var a Accumulator
+	assert.EqualValues(a.Count(), 0)
+	assert.EqualValues(a.Sum(), 0)
+	assert.True(math.IsNaN(float64(a.Mean())))
+	assert.True(math.IsNaN(float64(a.Min())))
+	assert.True(math.IsNaN(float64(a.Max())))
+	assert.True(math.IsNaN(float64(a.Variance())))
+	a.Add(2, 4, 4, 4, 5, 5, 7, 9)
+	assert.EqualValues(a.Count(), 8)
+	assert.EqualValues(a.Sum(), 40)
+	assert.EqualValues(a.Mean(), 5)
+	assert.EqualValues(a.Min(), 2)
+	assert.EqualValues(a.Max(), 9)
+	assert.EqualValues(a.Variance(), 4)
+	assert.EqualValues(a.StdDev(), 2)
+	assert.InDelta(float64(a.SampleVariance()), 32.0/7, 1e-12)
+	assert.InDelta(float64(a.SampleStdDev()), math.Sqrt(32.0/7), 1e-12)
+	a.Reset()
+	assert.EqualValues(a.Count(), 0)
+	a.Add(-3)
+	assert.EqualValues(a.Min(), -3)
+	assert.EqualValues(a.Max(), -3)
+	assert.EqualValues(a.Variance(), 0)
+	assert.True(math.IsNaN(float64(a.SampleVariance())))
+}
+
+func TestAccumulatorPrecision(t *testing.T) {
+	assert := assert.New(t)
+	var a Accumulator
+	a.Add(1)
+	for i := 0; i < 1000000; i++ {
+		a.Add(1e-16)
+	}
+	assert.EqualValues(a.Sum(), 1+1e-10)
+	a.Reset()
+	a.Add(1e100, 1, -1e100)
+	assert.EqualValues(a.Sum(), 1)
+	// large offset does not hurt the variance
+	a.Reset()
+	a.Add(1e9+4, 1e9+7, 1e9+13, 1e9+16)
+	assert.EqualValues(a.Mean(), 1e9+10)
+	assert.EqualValues(a.Variance(), 22.5)
+}
+
+func TestAccumulatorMerge(t *testing.T) {
+	assert := assert.New(t)
+	values := []Number{3, 1, 4, 1, 5, 9, 2, 6, 5, 3, 5, 8, 9, 7, 9}
+	var all Accumulator
+	all.Add(values...)
+	var parts [3]Accumulator
+	for i, v := range values {
+		parts[i%3].Add(v)
+	}
+	var merged Accumulator
+	merged.Merge(Accumulator{})
+	assert.EqualValues(merged.Count(), 0)
+	for _, p := range parts {
+		merged.Merge(p)
+	}
+	merged.Merge(Accumulator{})
+	assert.Equal(merged.Count(), all.Count())
+	assert.Equal(merged.Sum(), all.Sum())
+	assert.Equal(merged.Min(), all.Min())
+	assert.Equal(merged.Max(), all.Max())
+	assert.InDelta(float64(merged.Mean()), float64(all.Mean()), 1e-12)
+	assert.InDelta(float64(merged.Variance()), float64(all.Variance()), 1e-12)
+}
+
+func TestAccumulatorMarshalBinary(t *testing.T) {
+	assert := assert.New(t)
+	var a Accumulator
+	a.Add(1, 2, 3, 4)
+	data, err := a.MarshalBinary()
+	assert.NoError(err)
+	assert.Len(data, 4+8+6*8+2)
+	var b Accumulator
+	assert.NoError(b.UnmarshalBinary(data))
+	assert.Equal(a, b)
+	b.Add(5)
+	assert.EqualValues(b.Mean(), 3)
+	assert.Error(b.UnmarshalBinary(data[:len(data)-1]))
+	data[10]++
+	assert.EqualError(b.UnmarshalBinary(data),
+		"core.Accumulator.UnmarshalBinary: CRC check fail")
+	assert.EqualError(b.UnmarshalBinary([]byte{0x01, 0x00, 0x00, 0x00}),
+		"core.Accumulator.UnmarshalBinary: version error")
+	assert.EqualValues(b.Count(), 5)
+}
+
+func TestPercentile(t *testing.T) {
+	assert := assert.New(t)
+	values := []Number{15, 20, 35, 40, 50}
+	assert.EqualValues(Percentile(values, 0), 15)
+	assert.EqualValues(Percentile(values, 100), 50)
+	assert.EqualValues(Percentile(values, 50), 35)
+	assert.EqualValues(Percentile(values, 25), 20)
+	assert.EqualValues(Percentile(values, 40), 29)
+	assert.EqualValues(Percentile(values, 90), 46)
+	assert.True(math.IsNaN(float64(Percentile(values, -1))))
+	assert.True(math.IsNaN(float64(Percentile(values, 101))))
+	assert.True(math.IsNaN(float64(Percentile(values, math.NaN()))))
+	assert.True(math.IsNaN(float64(Percentile(nil, 50))))
+	assert.EqualValues(Percentile([]Number{7}, 30), 7)
+	assert.Equal(Percentiles([]Number{50, 40, 35, 20, 15}, 0, 50, 90, 100),
+		[]Number{15, 35, 46, 50})
+	assert.Equal(Percentiles(nil), []Number{})
+}
+
+func TestMedian(t *testing.T) {
+	assert := assert.New(t)
+	values := []Number{5, 1, 3}
+	assert.EqualValues(Median(values), 3)
+	assert.Equal(values, []Number{5, 1, 3})
+	assert.EqualValues(Median([]Number{4, 1, 3, 2}), 2.5)
+	assert.True(math.IsNaN(float64(Median(nil))))
+}
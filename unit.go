@@ -0,0 +1,274 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Dimension holds the exponents of the SI base units, in the order of
+// DimensionSymbols.
+type Dimension [7]int8
+
+var DimensionSymbols = [7]string{"m", "kg", "s", "A", "K", "mol", "cd"}
+
+func (d Dimension) Mul(o Dimension) Dimension {
+	for i := range d {
+		d[i] += o[i]
+	}
+	return d
+}
+
+func (d Dimension) Div(o Dimension) Dimension {
+	for i := range d {
+		d[i] -= o[i]
+	}
+	return d
+}
+
+func (d Dimension) Pow(n int) Dimension {
+	for i := range d {
+		d[i] *= int8(n)
+	}
+	return d
+}
+
+func (d Dimension) IsDimensionless() bool {
+	return d == Dimension{}
+}
+
+// String returns the dimension in SI base units, e.g. "kg·m^2·s^-2", or
+// "1" when dimensionless. The result is accepted by ParseUnit.
+func (d Dimension) String() string {
+	// Show kg first, "kg·m^2·s^-2" reads better than "m^2·kg·s^-2".
+	order := []int{1, 0, 2, 3, 4, 5, 6}
+	var parts []string
+	for _, i := range order {
+		switch d[i] {
+		case 0:
+		case 1:
+			parts = append(parts, DimensionSymbols[i])
+		default:
+			parts = append(parts,
+				DimensionSymbols[i]+"^"+strconv.Itoa(int(d[i])))
+		}
+	}
+	if len(parts) == 0 {
+		return "1"
+	}
+	return strings.Join(parts, "·")
+}
+
+// Unit converts values to SI: si = value * Factor + Offset. Offset is only
+// used by temperature scales such as °C and °F. Prefixable units accept SI
+// prefixes, e.g. "kPa" or "mV".
+type Unit struct {
+	Symbol     string
+	Dimension  Dimension
+	Factor     float64
+	Offset     float64
+	Prefixable bool
+}
+
+func (u *Unit) ToSI(v Number) Number {
+	return v*Number(u.Factor) + Number(u.Offset)
+}
+
+func (u *Unit) FromSI(v Number) Number {
+	return (v - Number(u.Offset)) / Number(u.Factor)
+}
+
+// Compatible reports whether values of u and o can be converted to each
+// other.
+func (u *Unit) Compatible(o *Unit) bool {
+	return u.Dimension == o.Dimension
+}
+
+func (u *Unit) String() string {
+	return u.Symbol
+}
+
+type unitPrefix struct {
+	Symbol string
+	Factor float64
+}
+
+var unitPrefixes = []unitPrefix{
+	{"da", 1e1}, // must be tried before "d"
+	{"Y", 1e24}, {"Z", 1e21}, {"E", 1e18}, {"P", 1e15}, {"T", 1e12},
+	{"G", 1e9}, {"M", 1e6}, {"k", 1e3}, {"h", 1e2},
+	{"d", 1e-1}, {"c", 1e-2}, {"m", 1e-3},
+	{"µ", 1e-6}, {"μ", 1e-6}, {"u", 1e-6},
+	{"n", 1e-9}, {"p", 1e-12}, {"f", 1e-15}, {"a", 1e-18},
+	{"z", 1e-21}, {"y", 1e-24},
+}
+
+var units = map[string]*Unit{}
+
+// Dimensionless is the unit of plain numbers, its symbol is empty.
+var Dimensionless = &Unit{Symbol: "", Factor: 1}
+
+func init() {
+	dim := func(m, kg, s, a, k, mol, cd int8) Dimension {
+		return Dimension{m, kg, s, a, k, mol, cd}
+	}
+	add := func(symbol string, d Dimension, factor, offset float64,
+		prefixable bool, aliases ...string) {
+		u := &Unit{symbol, d, factor, offset, prefixable}
+		units[symbol] = u
+		for _, alias := range aliases {
+			units[alias] = u
+		}
+	}
+	// base units, kg is g with the k prefix
+	add("m", dim(1, 0, 0, 0, 0, 0, 0), 1, 0, true)
+	add("g", dim(0, 1, 0, 0, 0, 0, 0), 1e-3, 0, true)
+	add("s", dim(0, 0, 1, 0, 0, 0, 0), 1, 0, true)
+	add("A", dim(0, 0, 0, 1, 0, 0, 0), 1, 0, true)
+	add("K", dim(0, 0, 0, 0, 1, 0, 0), 1, 0, true)
+	add("mol", dim(0, 0, 0, 0, 0, 1, 0), 1, 0, true)
+	add("cd", dim(0, 0, 0, 0, 0, 0, 1), 1, 0, true)
+	// derived units
+	add("Hz", dim(0, 0, -1, 0, 0, 0, 0), 1, 0, true)
+	add("N", dim(1, 1, -2, 0, 0, 0, 0), 1, 0, true)
+	add("Pa", dim(-1, 1, -2, 0, 0, 0, 0), 1, 0, true)
+	add("J", dim(2, 1, -2, 0, 0, 0, 0), 1, 0, true)
+	add("W", dim(2, 1, -3, 0, 0, 0, 0), 1, 0, true)
+	add("C", dim(0, 0, 1, 1, 0, 0, 0), 1, 0, true)
+	add("V", dim(2, 1, -3, -1, 0, 0, 0), 1, 0, true)
+	add("F", dim(-2, -1, 4, 2, 0, 0, 0), 1, 0, true)
+	add("Ω", dim(2, 1, -3, -2, 0, 0, 0), 1, 0, true, "ohm")
+	add("S", dim(-2, -1, 3, 2, 0, 0, 0), 1, 0, true)
+	add("Wb", dim(2, 1, -2, -1, 0, 0, 0), 1, 0, true)
+	add("T", dim(0, 1, -2, -1, 0, 0, 0), 1, 0, true)
+	add("H", dim(2, 1, -2, -2, 0, 0, 0), 1, 0, true)
+	// common non-SI units
+	add("%", Dimension{}, 1e-2, 0, false)
+	add("ppm", Dimension{}, 1e-6, 0, false)
+	add("min", dim(0, 0, 1, 0, 0, 0, 0), 60, 0, false)
+	add("h", dim(0, 0, 1, 0, 0, 0, 0), 3600, 0, false)
+	add("d", dim(0, 0, 1, 0, 0, 0, 0), 86400, 0, false)
+	add("L", dim(3, 0, 0, 0, 0, 0, 0), 1e-3, 0, true, "l")
+	add("t", dim(0, 1, 0, 0, 0, 0, 0), 1e3, 0, false)
+	add("bar", dim(-1, 1, -2, 0, 0, 0, 0), 1e5, 0, true)
+	add("atm", dim(-1, 1, -2, 0, 0, 0, 0), 101325, 0, false)
+	add("psi", dim(-1, 1, -2, 0, 0, 0, 0), 6894.757293168361, 0, false)
+	add("Wh", dim(2, 1, -2, 0, 0, 0, 0), 3600, 0, true)
+	add("VA", dim(2, 1, -3, 0, 0, 0, 0), 1, 0, true)
+	add("Ah", dim(0, 0, 1, 1, 0, 0, 0), 3600, 0, true)
+	add("rpm", dim(0, 0, -1, 0, 0, 0, 0), 1.0/60, 0, false)
+	add("°C", dim(0, 0, 0, 0, 1, 0, 0), 1, 273.15, false, "℃", "degC")
+	add("°F", dim(0, 0, 0, 0, 1, 0, 0), 5.0/9, 459.67*5/9, false,
+		"℉", "degF")
+}
+
+// RegisterUnit adds u to the registry used by LookupUnit and ParseUnit.
+func RegisterUnit(u Unit) error {
+	if u.Symbol == "" || u.Factor == 0 {
+		return errors.New("core.RegisterUnit: invalid unit")
+	}
+	if _, ok := units[u.Symbol]; ok {
+		return fmt.Errorf("core.RegisterUnit: %q already registered", u.Symbol)
+	}
+	units[u.Symbol] = &u
+	return nil
+}
+
+// LookupUnit finds a registered unit by symbol, optionally with an SI
+// prefix. An exact match wins, so "min" is minute and "cd" is candela.
+func LookupUnit(symbol string) (*Unit, error) {
+	if symbol == "" {
+		return Dimensionless, nil
+	}
+	if u, ok := units[symbol]; ok {
+		return u, nil
+	}
+	for _, p := range unitPrefixes {
+		if !strings.HasPrefix(symbol, p.Symbol) {
+			continue
+		}
+		u, ok := units[symbol[len(p.Symbol):]]
+		if ok && u.Prefixable {
+			return &Unit{Symbol: symbol, Dimension: u.Dimension,
+				Factor: u.Factor * p.Factor}, nil
+		}
+	}
+	return nil, fmt.Errorf("core.LookupUnit: unknown unit %q", symbol)
+}
+
+// ParseUnit parses a unit expression such as "kPa", "m/s", "km/h",
+// "kg·m^2·s^-2" or "m²". Factors are separated by "·" or "*", everything
+// after a "/" is in the denominator. Units with an offset can not be part of
+// an expression.
+func ParseUnit(s string) (*Unit, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || s == "1" {
+		return Dimensionless, nil
+	}
+	if u, err := LookupUnit(s); err == nil {
+		return u, nil
+	}
+	ret := &Unit{Symbol: s, Factor: 1}
+	for i, part := range strings.Split(s, "/") {
+		sign := 1
+		if i > 0 {
+			sign = -1
+		}
+		part = strings.Replace(part, "*", "·", -1)
+		for _, factor := range strings.Split(part, "·") {
+			sym, exp, err := parseUnitFactor(strings.TrimSpace(factor))
+			if err != nil {
+				return nil, err
+			}
+			if sym == "1" && exp == 1 {
+				continue
+			}
+			u, err := LookupUnit(sym)
+			if err != nil || sym == "" {
+				return nil, fmt.Errorf("core.ParseUnit: unknown unit %q in %q",
+					sym, s)
+			}
+			if u.Offset != 0 {
+				return nil, fmt.Errorf(
+					"core.ParseUnit: %q can not be used in %q", sym, s)
+			}
+			exp *= sign
+			ret.Dimension = ret.Dimension.Mul(u.Dimension.Pow(exp))
+			ret.Factor *= math.Pow(u.Factor, float64(exp))
+		}
+	}
+	return ret, nil
+}
+
+func parseUnitFactor(s string) (string, int, error) {
+	if i := strings.Index(s, "^"); i >= 0 {
+		exp, err := strconv.Atoi(s[i+1:])
+		if err != nil {
+			return "", 0, fmt.Errorf("core.ParseUnit: invalid exponent in %q", s)
+		}
+		return s[:i], exp, nil
+	}
+	for suffix, exp := range map[string]int{"²": 2, "³": 3} {
+		if strings.HasSuffix(s, suffix) {
+			return strings.TrimSuffix(s, suffix), exp, nil
+		}
+	}
+	return s, 1, nil
+}
+
+// coherentUnit returns the SI unit with factor 1 for d, using the name of a
+// derived unit when there is one, e.g. "J" instead of "kg·m^2·s^-2".
+func coherentUnit(d Dimension) *Unit {
+	if d.IsDimensionless() {
+		return Dimensionless
+	}
+	for _, sym := range []string{"m", "s", "A", "K", "mol", "cd", "N", "Pa",
+		"J", "W", "C", "V", "F", "Ω", "S", "Wb", "T", "H", "Hz"} {
+		if u := units[sym]; u.Dimension == d {
+			return u
+		}
+	}
+	return &Unit{Symbol: d.String(), Dimension: d, Factor: 1}
+}
@@ -0,0 +1,131 @@
+package core
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDimension(t *testing.T) {
+	assert := assert.New(t)
+	energy := Dimension{2, 1, -2, 0, 0, 0, 0}
+	assert.Equal(energy.String(), "kg·m^2·s^-2")
+	assert.Equal(Dimension{}.String(), "1")
+	assert.Equal(Dimension{1, 0, -1}.String(), "m·s^-1")
+	assert.True(Dimension{}.IsDimensionless())
+	assert.False(energy.IsDimensionless())
+	time := Dimension{0, 0, 1}
+	assert.Equal(energy.Div(time), Dimension{2, 1, -3, 0, 0, 0, 0})
+	assert.Equal(energy.Div(time).Mul(time), energy)
+	assert.Equal(Dimension{1}.Pow(3), Dimension{3})
+}
+
+func TestLookupUnit(t *testing.T) {
+	assert := assert.New(t)
+	check := func(symbol string, factor float64, d Dimension) {
+		u, err := LookupUnit(symbol)
+		if assert.NoError(err, symbol) {
+			assert.Equal(symbol, u.Symbol)
+			assert.InEpsilon(factor, u.Factor, 1e-12, symbol)
+			assert.Equal(d, u.Dimension, symbol)
+		}
+	}
+	check("m", 1, Dimension{1})
+	check("km", 1e3, Dimension{1})
+	check("mm", 1e-3, Dimension{1})
+	check("µm", 1e-6, Dimension{1})
+	check("um", 1e-6, Dimension{1})
+	check("dam", 10, Dimension{1})
+	check("kg", 1, Dimension{0, 1})
+	check("g", 1e-3, Dimension{0, 1})
+	check("ms", 1e-3, Dimension{0, 0, 1})
+	check("min", 60, Dimension{0, 0, 1})
+	check("h", 3600, Dimension{0, 0, 1})
+	check("cd", 1, Dimension{0, 0, 0, 0, 0, 0, 1})
+	check("mV", 1e-3, Dimension{2, 1, -3, -1})
+	check("kPa", 1e3, Dimension{-1, 1, -2})
+	check("hPa", 1e2, Dimension{-1, 1, -2})
+	check("mbar", 100, Dimension{-1, 1, -2})
+	check("kWh", 3.6e6, Dimension{2, 1, -2})
+	check("MW", 1e6, Dimension{2, 1, -3})
+	check("mA", 1e-3, Dimension{0, 0, 0, 1})
+	check("mAh", 3.6, Dimension{0, 0, 1, 1})
+	check("kΩ", 1e3, Dimension{2, 1, -3, -2})
+	check("mL", 1e-6, Dimension{3})
+	check("%", 1e-2, Dimension{})
+	check("°C", 1, Dimension{0, 0, 0, 0, 1})
+	u, err := LookupUnit("degC")
+	assert.NoError(err)
+	assert.Equal(u.Symbol, "°C")
+	u, err = LookupUnit("")
+	assert.NoError(err)
+	assert.Equal(u, Dimensionless)
+	for _, s := range []string{"x", "k°C", "kh", "kmin", "m%", "kk"} {
+		_, err = LookupUnit(s)
+		assert.Error(err, s)
+	}
+	_, err = LookupUnit("xyz")
+	assert.EqualError(err, `core.LookupUnit: unknown unit "xyz"`)
+}
+
+func TestParseUnit(t *testing.T) {
+	assert := assert.New(t)
+	check := func(s string, factor float64, d Dimension) {
+		u, err := ParseUnit(s)
+		if assert.NoError(err, s) {
+			assert.InEpsilon(factor, u.Factor, 1e-12, s)
+			assert.Equal(d, u.Dimension, s)
+		}
+	}
+	check("m/s", 1, Dimension{1, 0, -1})
+	check("km/h", 1/3.6, Dimension{1, 0, -1})
+	check("m/s^2", 1, Dimension{1, 0, -2})
+	check("m/s/s", 1, Dimension{1, 0, -2})
+	check("m²", 1, Dimension{2})
+	check("cm³", 1e-6, Dimension{3})
+	check("N·m", 1, Dimension{2, 1, -2})
+	check("N*m", 1, Dimension{2, 1, -2})
+	check("kg·m^2·s^-2", 1, Dimension{2, 1, -2})
+	check("1/s", 1, Dimension{0, 0, -1})
+	check(" kPa ", 1e3, Dimension{-1, 1, -2})
+	check("1", 1, Dimension{})
+	check("", 1, Dimension{})
+	u, err := ParseUnit("km/h")
+	assert.NoError(err)
+	assert.Equal(u.Symbol, "km/h")
+	_, err = ParseUnit("°C/s")
+	assert.EqualError(err, `core.ParseUnit: "°C" can not be used in "°C/s"`)
+	_, err = ParseUnit("m/x")
+	assert.EqualError(err, `core.ParseUnit: unknown unit "x" in "m/x"`)
+	_, err = ParseUnit("m^x")
+	assert.Error(err)
+	_, err = ParseUnit("m/")
+	assert.Error(err)
+}
+
+func TestRegisterUnit(t *testing.T) {
+	assert := assert.New(t)
+	assert.NoError(RegisterUnit(Unit{Symbol: "testunit", Factor: 0.3048,
+		Dimension: Dimension{1}}))
+	u, err := ParseUnit("testunit/s")
+	assert.NoError(err)
+	assert.InEpsilon(u.Factor, 0.3048, 1e-12)
+	assert.EqualError(RegisterUnit(Unit{Symbol: "testunit", Factor: 1}),
+		`core.RegisterUnit: "testunit" already registered`)
+	assert.Error(RegisterUnit(Unit{Symbol: "", Factor: 1}))
+	assert.Error(RegisterUnit(Unit{Symbol: "zero"}))
+}
+
+func TestUnitConversion(t *testing.T) {
+	assert := assert.New(t)
+	c, _ := LookupUnit("°C")
+	f, _ := LookupUnit("°F")
+	assert.InDelta(float64(c.ToSI(0)), 273.15, 1e-12)
+	assert.InDelta(float64(f.ToSI(32)), 273.15, 1e-12)
+	assert.InDelta(float64(f.FromSI(c.ToSI(100))), 212, 1e-12)
+	assert.InDelta(float64(c.FromSI(f.ToSI(-40))), -40, 1e-12)
+	assert.True(c.Compatible(f))
+	k, _ := LookupUnit("K")
+	assert.True(c.Compatible(k))
+	m, _ := LookupUnit("m")
+	assert.False(c.Compatible(m))
+}
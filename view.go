@@ -0,0 +1,169 @@
+package core
+
+import (
+	"fmt"
+)
+
+// ByteView is a read-only window of a ByteArray. It shares the memory of
+// the ByteArray, so it is cheap to hand out, but offers no way to modify it;
+// Clone returns a mutable copy. Changes made to the ByteArray through other
+// references are visible in the view.
+type ByteView struct {
+	data ByteArray
+}
+
+// View returns a ByteView of the whole ba.
+func (ba ByteArray) View() ByteView {
+	return ByteView{ba[:len(ba):len(ba)]}
+}
+
+// ViewRange returns a ByteView of ba[start:end].
+func (ba ByteArray) ViewRange(start, end int) (ByteView, error) {
+	return ba.View().slice("ViewRange", start, end)
+}
+
+func (v ByteView) slice(name string, start, end int) (ByteView, error) {
+	if start < 0 || end < start || end > len(v.data) {
+		return ByteView{}, fmt.Errorf(
+			"core.ByteView.%s: range [%d, %d) out of bounds [0, %d)",
+			name, start, end, len(v.data))
+	}
+	return ByteView{v.data[start:end:end]}, nil
+}
+
+// Slice returns the sub-view [start, end) of v.
+func (v ByteView) Slice(start, end int) (ByteView, error) {
+	return v.slice("Slice", start, end)
+}
+
+func (v ByteView) Len() int {
+	return len(v.data)
+}
+
+func (v ByteView) At(i int) (byte, error) {
+	if i < 0 || i >= len(v.data) {
+		return 0, fmt.Errorf("core.ByteView.At: index %d out of bounds [0, %d)",
+			i, len(v.data))
+	}
+	return v.data[i], nil
+}
+
+// Clone returns a mutable copy of the bytes of v.
+func (v ByteView) Clone() ByteArray {
+	return v.data.Clone()
+}
+
+// CopyTo copies the bytes of v to dst and returns the number copied.
+func (v ByteView) CopyTo(dst []byte) int {
+	return copy(dst, v.data)
+}
+
+// Reader returns a ByteArrayReader over v for typed reads. The slices
+// returned by its Peek share the memory of v and must not be modified.
+func (v ByteView) Reader() *ByteArrayReader {
+	return NewByteArrayReader(v.data)
+}
+
+func (v ByteView) ToStringEx(
+	withLen bool, sep string, prefix string, suffix string) string {
+	return v.data.ToStringEx(withLen, sep, prefix, suffix)
+}
+
+func (v ByteView) ToString() string {
+	return v.data.ToString()
+}
+
+// Format implements the fmt.Formatter interface like ByteArray.Format.
+func (v ByteView) Format(f fmt.State, verb rune) {
+	v.data.Format(f, verb)
+}
+
+func (v ByteView) Hexdump() string {
+	return v.data.Hexdump()
+}
+
+func (v ByteView) HexdumpEx(opt HexdumpOptions) string {
+	return v.data.HexdumpEx(opt)
+}
+
+func (v ByteView) Encode(e Encoding) string {
+	return v.data.Encode(e)
+}
+
+func (v ByteView) Crc8() byte {
+	return v.data.Crc8()
+}
+
+func (v ByteView) Crc16() uint16 {
+	return v.data.Crc16()
+}
+
+func (v ByteView) Crc32() uint32 {
+	return v.data.Crc32()
+}
+
+func (v ByteView) Sum8() byte {
+	return v.data.Sum8()
+}
+
+func (v ByteView) Sum8Complement() byte {
+	return v.data.Sum8Complement()
+}
+
+func (v ByteView) Xor8() byte {
+	return v.data.Xor8()
+}
+
+func (v ByteView) LRC() byte {
+	return v.data.LRC()
+}
+
+func (v ByteView) Fletcher16() uint16 {
+	return v.data.Fletcher16()
+}
+
+func (v ByteView) Fletcher32() uint32 {
+	return v.data.Fletcher32()
+}
+
+func (v ByteView) Adler32() uint32 {
+	return v.data.Adler32()
+}
+
+func (v ByteView) Index(sep []byte) int {
+	return v.data.Index(sep)
+}
+
+func (v ByteView) LastIndex(sep []byte) int {
+	return v.data.LastIndex(sep)
+}
+
+func (v ByteView) Count(sep []byte) int {
+	return v.data.Count(sep)
+}
+
+func (v ByteView) IndexPattern(p Pattern) int {
+	return v.data.IndexPattern(p)
+}
+
+func (v ByteView) Bit(i int, order BitOrder) (bool, error) {
+	return v.data.Bit(i, order)
+}
+
+func (v ByteView) Uint(offset, n int, order BitOrder) (uint64, error) {
+	return v.data.Uint(offset, n, order)
+}
+
+func (v ByteView) Int(offset, n int, order BitOrder) (int64, error) {
+	return v.data.Int(offset, n, order)
+}
+
+func (v ByteView) Registers(offset, count int) ([]uint16, error) {
+	return v.data.Registers(offset, count)
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface with the
+// layout of ByteArray.
+func (v ByteView) MarshalBinary() ([]byte, error) {
+	return v.data.MarshalBinary()
+}
@@ -0,0 +1,102 @@
+package core
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestByteView(t *testing.T) {
+	assert := assert.New(t)
+	ba := ByteArray{0, 1, 2, 3, 4, 5, 6, 7}
+	v := ba.View()
+	assert.Equal(v.Len(), 8)
+	assert.Equal(v.ToString(), ba.ToString())
+	v, err := ba.ViewRange(2, 6)
+	assert.NoError(err)
+	assert.Equal(v.ToString(), "[4]02 03 04 05")
+	b, err := v.At(3)
+	assert.NoError(err)
+	assert.Equal(b, byte(5))
+	_, err = v.At(4)
+	assert.EqualError(err, "core.ByteView.At: index 4 out of bounds [0, 4)")
+	_, err = v.At(-1)
+	assert.Error(err)
+
+	sub, err := v.Slice(1, 3)
+	assert.NoError(err)
+	assert.Equal(sub.ToString(), "[2]03 04")
+	sub, err = v.Slice(4, 4)
+	assert.NoError(err)
+	assert.Equal(sub.Len(), 0)
+	_, err = v.Slice(2, 5)
+	assert.EqualError(err, "core.ByteView.Slice: range [2, 5) out of bounds [0, 4)")
+	_, err = v.Slice(3, 2)
+	assert.Error(err)
+	_, err = v.Slice(-1, 2)
+	assert.Error(err)
+	_, err = ba.ViewRange(0, 9)
+	assert.EqualError(err,
+		"core.ByteView.ViewRange: range [0, 9) out of bounds [0, 8)")
+
+	// the view shares memory, Clone does not
+	c := v.Clone()
+	c[0] = 0xFF
+	assert.Equal(ba[2], byte(2))
+	ba[2] = 0xEE
+	b, _ = v.At(0)
+	assert.Equal(b, byte(0xEE))
+	dst := make([]byte, 2)
+	assert.Equal(v.CopyTo(dst), 2)
+	assert.Equal(dst, []byte{0xEE, 3})
+	// appending to a clone never touches the parent
+	c = append(v.Clone(), 9)
+	assert.Len(c, 5)
+	assert.Equal(ba[6], byte(6))
+}
+
+func TestByteViewReadOnlyMethods(t *testing.T) {
+	assert := assert.New(t)
+	ba := ByteArray{0xFF, 0x12, 0x34, 0xAA, 0x55, 0x00}
+	v, _ := ba.ViewRange(1, 5)
+	w := ByteArray{0x12, 0x34, 0xAA, 0x55}
+	assert.Equal(v.ToStringEx(false, ",", "0x", ""), w.ToStringEx(false, ",", "0x", ""))
+	assert.Equal(fmt.Sprintf("%v %s %x", v, v, v), "[4]12 34 AA 55 12 34 AA 55 1234aa55")
+	assert.Equal(v.Hexdump(), w.Hexdump())
+	assert.Equal(v.HexdumpEx(HexdumpOptions{}), "12 34 AA 55\n")
+	assert.Equal(v.Encode(EncodingBase64), w.Encode(EncodingBase64))
+	assert.Equal(v.Crc8(), w.Crc8())
+	assert.Equal(v.Crc16(), w.Crc16())
+	assert.Equal(v.Crc32(), w.Crc32())
+	assert.Equal(v.Sum8(), w.Sum8())
+	assert.Equal(v.Sum8Complement(), w.Sum8Complement())
+	assert.Equal(v.Xor8(), w.Xor8())
+	assert.Equal(v.LRC(), w.LRC())
+	assert.Equal(v.Fletcher16(), w.Fletcher16())
+	assert.Equal(v.Fletcher32(), w.Fletcher32())
+	assert.Equal(v.Adler32(), w.Adler32())
+	assert.Equal(v.Index([]byte{0xAA}), 2)
+	assert.Equal(v.LastIndex([]byte{0x12}), 0)
+	assert.Equal(v.Count([]byte{0x55}), 1)
+	assert.Equal(v.IndexPattern(MustParsePattern("A? 5?")), 2)
+	bit, err := v.Bit(3, MSB0)
+	assert.NoError(err)
+	assert.True(bit)
+	u, err := v.Uint(0, 16, MSB0)
+	assert.NoError(err)
+	assert.EqualValues(u, 0x1234)
+	i, err := v.Int(16, 8, MSB0)
+	assert.NoError(err)
+	assert.EqualValues(i, -86)
+	regs, err := v.Registers(0, 2)
+	assert.NoError(err)
+	assert.Equal(regs, []uint16{0x1234, 0xAA55})
+	r := v.Reader()
+	assert.Equal(r.ReadUint16BE(), uint16(0x1234))
+	assert.Equal(r.ReadUint16LE(), uint16(0x55AA))
+	assert.Equal(r.Remaining(), 0)
+	data, err := v.MarshalBinary()
+	assert.NoError(err)
+	expect, _ := w.MarshalBinary()
+	assert.Equal(data, expect)
+}
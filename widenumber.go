@@ -0,0 +1,191 @@
+package core
+
+import (
+	"bytes"
+	"errors"
+	"github.com/newkedison/core/algorithm"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+)
+
+// WideNumber is a Number which also keeps integers exact when they do not
+// fit in the 53-bit significand of a float64, such as 64-bit counters. Values
+// which a Number can hold exactly are stored as a Number and serialize with
+// the Number layout, so a WideNumber can read anything written by Number.
+type WideNumber struct {
+	n   Number
+	big *big.Int // non-nil only for integers not exact as float64
+}
+
+// NewWideNumber accepts everything NewNumber does, without the range limit
+// on int64 and uint64, plus *big.Int, Number and WideNumber.
+func NewWideNumber(d interface{}) WideNumber {
+	switch v := d.(type) {
+	case WideNumber:
+		return v
+	case int64:
+		return NewWideNumberFromBigInt(big.NewInt(v))
+	case uint64:
+		return NewWideNumberFromBigInt(new(big.Int).SetUint64(v))
+	case int:
+		return NewWideNumberFromBigInt(big.NewInt(int64(v)))
+	case uint:
+		return NewWideNumberFromBigInt(new(big.Int).SetUint64(uint64(v)))
+	case *big.Int:
+		return NewWideNumberFromBigInt(v)
+	}
+	if reflect.TypeOf(d).ConvertibleTo(NumberType) {
+		return WideNumber{n: NewNumber(d)}
+	}
+	panic("Invalid number type")
+}
+
+func NewWideNumberFromBigInt(i *big.Int) WideNumber {
+	f, acc := new(big.Float).SetInt(i).Float64()
+	if acc == big.Exact {
+		return WideNumber{n: Number(f)}
+	}
+	return WideNumber{big: new(big.Int).Set(i)}
+}
+
+// IsWide reports whether the value is an integer which can not be held by a
+// Number without losing digits.
+func (w WideNumber) IsWide() bool {
+	return w.big != nil
+}
+
+// ToNumber returns the nearest Number.
+func (w WideNumber) ToNumber() Number {
+	if w.big == nil {
+		return w.n
+	}
+	f, _ := new(big.Float).SetInt(w.big).Float64()
+	return Number(f)
+}
+
+// BigInt returns the value as an integer, ok is false when it has a
+// fractional part or is NaN or Inf.
+func (w WideNumber) BigInt() (i *big.Int, ok bool) {
+	if w.big != nil {
+		return new(big.Int).Set(w.big), true
+	}
+	f := float64(w.n)
+	if math.IsNaN(f) || math.IsInf(f, 0) || f != math.Trunc(f) {
+		return nil, false
+	}
+	i, _ = big.NewFloat(f).Int(nil)
+	return i, true
+}
+
+// ToInt64 returns the exact value, ok is false when it is not an integer or
+// out of the range of int64.
+func (w WideNumber) ToInt64() (v int64, ok bool) {
+	i, ok := w.BigInt()
+	if !ok || !i.IsInt64() {
+		return 0, false
+	}
+	return i.Int64(), true
+}
+
+// ToUint64 returns the exact value, ok is false when it is not an integer or
+// out of the range of uint64.
+func (w WideNumber) ToUint64() (v uint64, ok bool) {
+	i, ok := w.BigInt()
+	if !ok || !i.IsUint64() {
+		return 0, false
+	}
+	return i.Uint64(), true
+}
+
+func (w WideNumber) bigFloat() *big.Float {
+	if w.big != nil {
+		return new(big.Float).SetInt(w.big)
+	}
+	return big.NewFloat(float64(w.n))
+}
+
+// Cmp compares w and o exactly and returns -1, 0 or 1. NaN is smaller than
+// any other value and equal to itself.
+func (w WideNumber) Cmp(o WideNumber) int {
+	wNaN := w.big == nil && math.IsNaN(float64(w.n))
+	oNaN := o.big == nil && math.IsNaN(float64(o.n))
+	switch {
+	case wNaN && oNaN:
+		return 0
+	case wNaN:
+		return -1
+	case oNaN:
+		return 1
+	}
+	return w.bigFloat().Cmp(o.bigFloat())
+}
+
+func (w WideNumber) String() string {
+	if w.big != nil {
+		return w.big.String()
+	}
+	f := float64(w.n)
+	if f == math.Trunc(f) && math.Abs(f) < 1e21 {
+		return big.NewFloat(f).Text('f', 0)
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+var wideNumberVersion int32 = 1
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface. Values
+// held as a Number use the Number layout (version 0), wide integers use
+// version 1: sign byte, uint32 length and the big-endian magnitude.
+func (w WideNumber) MarshalBinary() ([]byte, error) {
+	if w.big == nil {
+		return w.n.MarshalBinary()
+	}
+	buf := new(bytes.Buffer)
+	buf.Write(MarshalSimpleType(wideNumberVersion))
+	if w.big.Sign() < 0 {
+		buf.WriteByte(1)
+	} else {
+		buf.WriteByte(0)
+	}
+	mag := w.big.Bytes()
+	buf.Write(MarshalSimpleType(uint32(len(mag))))
+	buf.Write(mag)
+	return algorithm.AppendCrc16(buf.Bytes()), nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (w *WideNumber) UnmarshalBinary(data []byte) (err error) {
+	defer SetErrorWhenNotEnoughDataErrorPanic(
+		"core.WideNumber.UnmarshalBinary", &err)()
+	CheckBufferSize(data, 4)
+	var ver int32
+	offset := UnmashalSimpleType(&ver, data)
+	switch ver {
+	case 0:
+		var n Number
+		if err := n.UnmarshalBinary(data); err != nil {
+			return err
+		}
+		*w = WideNumber{n: n}
+	case 1:
+		CheckBufferSize(data, 1+4, offset)
+		negative := data[offset] != 0
+		offset++
+		var l uint32
+		offset += UnmashalSimpleType(&l, data[offset:])
+		CheckBufferSize(data, int(l)+2, offset)
+		if !algorithm.VerifyCrc16(data[:offset+int(l)+2]) {
+			return errors.New("core.WideNumber.UnmarshalBinary: CRC check fail")
+		}
+		i := new(big.Int).SetBytes(data[offset : offset+int(l)])
+		if negative {
+			i.Neg(i)
+		}
+		*w = NewWideNumberFromBigInt(i)
+	default:
+		return errors.New("core.WideNumber.UnmarshalBinary: version error")
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package core
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"math/big"
+	"testing"
+)
+
+func TestNewWideNumber(t *testing.T) {
+	assert := assert.New(t)
+	w := NewWideNumber(1.5)
+	assert.False(w.IsWide())
+	assert.EqualValues(w.ToNumber(), 1.5)
+	w = NewWideNumber(int64(math.MaxInt64))
+	assert.True(w.IsWide())
+	assert.Equal(w.String(), "9223372036854775807")
+	w = NewWideNumber(uint64(math.MaxUint64))
+	assert.True(w.IsWide())
+	assert.Equal(w.String(), "18446744073709551615")
+	assert.EqualValues(w.ToNumber(), math.Ldexp(1, 64))
+	w = NewWideNumber(int64(1) << 60)
+	assert.False(w.IsWide())
+	assert.Equal(w.String(), "1152921504606846976")
+	w = NewWideNumber(MaxIntNumber + 1)
+	assert.True(w.IsWide())
+	assert.Equal(w.String(), "10000000000000001")
+	w = NewWideNumber(int(-3))
+	assert.False(w.IsWide())
+	assert.Equal(w.String(), "-3")
+	w = NewWideNumber(uint(7))
+	assert.Equal(w.String(), "7")
+	i, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	w = NewWideNumber(i)
+	assert.True(w.IsWide())
+	i.SetInt64(0)
+	assert.Equal(w.String(), "123456789012345678901234567890")
+	assert.Equal(NewWideNumber(w), w)
+	assert.Equal(NewWideNumber(Number(2)).String(), "2")
+	assert.Equal(NewWideNumber(float32(0.5)).String(), "0.5")
+	assert.Equal(NewWideNumber(1e300).String(), "1e+300")
+	assert.Panics(func() { NewWideNumber("1") })
+	assert.Panics(func() { NewNumber(MaxIntNumber + 1) })
+}
+
+func TestWideNumberToInt(t *testing.T) {
+	assert := assert.New(t)
+	v, ok := NewWideNumber(int64(math.MaxInt64)).ToInt64()
+	assert.True(ok)
+	assert.EqualValues(v, int64(math.MaxInt64))
+	v, ok = NewWideNumber(int64(math.MinInt64)).ToInt64()
+	assert.True(ok)
+	assert.EqualValues(v, int64(math.MinInt64))
+	_, ok = NewWideNumber(uint64(math.MaxUint64)).ToInt64()
+	assert.False(ok)
+	u, ok := NewWideNumber(uint64(math.MaxUint64)).ToUint64()
+	assert.True(ok)
+	assert.EqualValues(u, uint64(math.MaxUint64))
+	_, ok = NewWideNumber(int64(-1)).ToUint64()
+	assert.False(ok)
+	v, ok = NewWideNumber(42.0).ToInt64()
+	assert.True(ok)
+	assert.EqualValues(v, 42)
+	_, ok = NewWideNumber(42.5).ToInt64()
+	assert.False(ok)
+	_, ok = NewWideNumber(math.NaN()).ToInt64()
+	assert.False(ok)
+	_, ok = NewWideNumber(math.Inf(1)).ToUint64()
+	assert.False(ok)
+	_, ok = NewWideNumber(1e30).ToUint64()
+	assert.False(ok)
+	i, ok := NewWideNumber(1e30).BigInt()
+	assert.True(ok)
+	assert.Equal(i.String(), "1000000000000000019884624838656")
+}
+
+func TestWideNumberCmp(t *testing.T) {
+	assert := assert.New(t)
+	a := NewWideNumber(uint64(1)<<63 + 1)
+	b := NewWideNumber(uint64(1)<<63 + 2)
+	// both convert to the same float64
+	assert.Equal(a.ToNumber(), b.ToNumber())
+	assert.Equal(a.Cmp(b), -1)
+	assert.Equal(b.Cmp(a), 1)
+	assert.Equal(a.Cmp(a), 0)
+	assert.Equal(a.Cmp(NewWideNumber(math.Ldexp(1, 63))), 1)
+	assert.Equal(NewWideNumber(1.5).Cmp(NewWideNumber(2)), -1)
+	assert.Equal(NewWideNumber(math.Inf(1)).Cmp(a), 1)
+	assert.Equal(NewWideNumber(math.Inf(-1)).Cmp(a), -1)
+	nan := NewWideNumber(math.NaN())
+	assert.Equal(nan.Cmp(nan), 0)
+	assert.Equal(nan.Cmp(a), -1)
+	assert.Equal(a.Cmp(nan), 1)
+}
+
+func TestWideNumberMarshalBinary(t *testing.T) {
+	assert := assert.New(t)
+	data, err := NewWideNumber(1).MarshalBinary()
+	assert.NoError(err)
+	expect, _ := Number(1).MarshalBinary()
+	assert.Equal(data, expect)
+	var w WideNumber
+	assert.NoError(w.UnmarshalBinary(data))
+	assert.Equal(w.String(), "1")
+
+	data, err = NewWideNumber(int64(-math.MaxInt64)).MarshalBinary()
+	assert.NoError(err)
+	assert.Equal(data[:4+1+4], []byte{0x01, 0x00, 0x00, 0x00, 0x01,
+		0x08, 0x00, 0x00, 0x00})
+	assert.Len(data, 4+1+4+8+2)
+	assert.NoError(w.UnmarshalBinary(data))
+	assert.Equal(w.String(), "-9223372036854775807")
+	v, ok := w.ToInt64()
+	assert.True(ok)
+	assert.EqualValues(v, -math.MaxInt64)
+
+	var n Number
+	assert.EqualError(n.UnmarshalBinary(data),
+		"core.Number.UnmarshalBinary: version error")
+	assert.Error(w.UnmarshalBinary(data[:len(data)-1]))
+	data[10]++
+	assert.EqualError(w.UnmarshalBinary(data),
+		"core.WideNumber.UnmarshalBinary: CRC check fail")
+	assert.EqualError(w.UnmarshalBinary([]byte{0x02, 0x00, 0x00, 0x00}),
+		"core.WideNumber.UnmarshalBinary: version error")
+	assert.Error(w.UnmarshalBinary([]byte{0x00, 0x00, 0x00, 0x00}))
+
+	w = NewWideNumber(uint64(math.MaxUint64))
+	data, err = MarshalObject(w)
+	assert.NoError(err)
+	var w2 WideNumber
+	assert.Equal(UnmarshalObject(&w2, data), len(data))
+	assert.Equal(w2.Cmp(w), 0)
+	assert.True(w2.IsWide())
+}